@@ -0,0 +1,209 @@
+// Package cloudupload implements resumable, chunked uploads of extracted
+// frames to a generic cloud backend, modeled on S3 multipart semantics: a
+// session is created once, frames are pushed as numbered parts that can be
+// retried independently, and the session is either completed or aborted.
+package cloudupload
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"semanticvideo/internal/ratelimit"
+)
+
+// FrameRef identifies one extracted frame to include in an upload part.
+type FrameRef struct {
+	FrameNumber int
+	FilePath    string
+	Timestamp   float64
+}
+
+// Uploader wraps chunked upload calls to a cloud backend rooted at baseURL,
+// authenticated with a bearer token.
+type Uploader struct {
+	baseURL string
+	token   string
+	http    *http.Client
+	limiter *ratelimit.Limiter
+}
+
+// NewUploader constructs an Uploader for baseURL, authenticating with token.
+// A nil limiter leaves requests unthrottled.
+func NewUploader(baseURL, token string, limiter *ratelimit.Limiter) *Uploader {
+	return &Uploader{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 60 * time.Second},
+		limiter: limiter,
+	}
+}
+
+// CreateSession opens a new multipart upload session for videoID and
+// returns its upload ID.
+func (u *Uploader) CreateSession(ctx context.Context, videoID string) (string, error) {
+	body, err := json.Marshal(map[string]string{"video_id": videoID})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := u.do(ctx, http.MethodPost, "/uploads", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		UploadID string `json:"upload_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("decode create session response: %w", err)
+	}
+	if payload.UploadID == "" {
+		return "", fmt.Errorf("cloud backend returned empty upload_id")
+	}
+	return payload.UploadID, nil
+}
+
+// UploadPart streams one batch of frames as a single multipart part and
+// returns the ETag the backend assigned it, to be passed to Complete.
+func (u *Uploader) UploadPart(ctx context.Context, uploadID string, partNum int, frames []FrameRef) (string, error) {
+	if len(frames) == 0 {
+		return "", fmt.Errorf("part %d has no frames", partNum)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for i, frame := range frames {
+		file, err := os.Open(frame.FilePath)
+		if err != nil {
+			return "", fmt.Errorf("open frame: %w", err)
+		}
+		part, err := writer.CreateFormFile("file", filepath.Base(frame.FilePath))
+		if err != nil {
+			file.Close()
+			return "", fmt.Errorf("create form file: %w", err)
+		}
+		_, copyErr := io.Copy(part, file)
+		file.Close()
+		if copyErr != nil {
+			return "", fmt.Errorf("copy frame: %w", copyErr)
+		}
+		_ = writer.WriteField(fmt.Sprintf("frame_number.%d", i), strconv.Itoa(frame.FrameNumber))
+		_ = writer.WriteField(fmt.Sprintf("timestamp.%d", i), strconv.FormatFloat(frame.Timestamp, 'f', -1, 64))
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("finalize form: %w", err)
+	}
+
+	path := fmt.Sprintf("/uploads/%s/parts/%d", uploadID, partNum)
+	resp, err := u.do(ctx, http.MethodPut, path, writer.FormDataContentType(), &body)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var payload struct {
+		ETag string `json:"etag"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("decode upload part response: %w", err)
+	}
+	if payload.ETag == "" {
+		return "", fmt.Errorf("cloud backend returned empty etag for part %d", partNum)
+	}
+	return payload.ETag, nil
+}
+
+// Complete finalizes uploadID given the ETag of every uploaded part, keyed
+// by part number.
+func (u *Uploader) Complete(ctx context.Context, uploadID string, etags map[int]string) error {
+	body, err := json.Marshal(map[string]interface{}{"parts": etags})
+	if err != nil {
+		return err
+	}
+	resp, err := u.do(ctx, http.MethodPost, fmt.Sprintf("/uploads/%s/complete", uploadID), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// Abort cancels an in-flight upload session, discarding any parts already
+// uploaded.
+func (u *Uploader) Abort(ctx context.Context, uploadID string) error {
+	resp, err := u.do(ctx, http.MethodPost, fmt.Sprintf("/uploads/%s/abort", uploadID), "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+func (u *Uploader) do(ctx context.Context, method, path, contentType string, body io.Reader) (*http.Response, error) {
+	if u.baseURL == "" {
+		return nil, fmt.Errorf("cloud base URL is empty")
+	}
+	if err := u.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if u.token != "" {
+		req.Header.Set("Authorization", "Bearer "+u.token)
+	}
+
+	resp, err := u.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cloud request: %w", err)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		defer resp.Body.Close()
+		errBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("cloud request to %s failed (%d): %s", path, resp.StatusCode, strings.TrimSpace(string(errBody)))
+	}
+	return resp, nil
+}
+
+// RetryWithBackoff calls fn until it succeeds, ctx is cancelled, or
+// maxAttempts is exhausted, sleeping an exponentially growing, jittered
+// delay between attempts.
+func RetryWithBackoff(ctx context.Context, maxAttempts int, baseDelay time.Duration, fn func() error) error {
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		lastErr = fn()
+		if lastErr == nil {
+			return nil
+		}
+		if attempt == maxAttempts-1 {
+			break
+		}
+		delay := baseDelay * time.Duration(1<<attempt)
+		jitter := time.Duration(rand.Int63n(int64(delay) / 2))
+		select {
+		case <-time.After(delay/2 + jitter):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("exhausted %d attempts: %w", maxAttempts, lastErr)
+}
@@ -0,0 +1,46 @@
+package cloudupload
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// UploadState is the resumable state of one video's in-progress or
+// completed cloud upload, persisted as .upload_state.json alongside the
+// video's extracted frames so a daemon restart resumes from the last acked
+// part instead of re-uploading everything.
+type UploadState struct {
+	VideoID        string         `json:"video_id"`
+	UploadID       string         `json:"upload_id"`
+	CompletedParts map[int]string `json:"completed_parts"`
+	Done           bool           `json:"done"`
+}
+
+// LoadState reads the upload state sidecar at path. A missing file is not an
+// error; it simply means no upload has started yet.
+func LoadState(path string) (*UploadState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var state UploadState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	if state.CompletedParts == nil {
+		state.CompletedParts = make(map[int]string)
+	}
+	return &state, nil
+}
+
+// SaveState writes state to path, overwriting any previous sidecar.
+func SaveState(path string, state *UploadState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
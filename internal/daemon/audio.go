@@ -0,0 +1,106 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"semanticvideo/internal/extract"
+	"semanticvideo/internal/transcribe"
+)
+
+// audioDirForVideo mirrors framesDirForVideo but for the segmented audio
+// extracted alongside frames.
+func (s *Server) audioDirForVideo(videoPath string) string {
+	return filepath.Join(s.framesDirForVideo(videoPath), "audio")
+}
+
+// extractAndIndexAudio extracts a PCM track and segmented WAV chunks for
+// videoPath, transcribes each segment, and pushes the resulting spans to the
+// vectordb tagged with modality "audio". It is a no-op unless audio indexing
+// is enabled in config.
+func (s *Server) extractAndIndexAudio(jobID, videoPath string, cancelCh <-chan struct{}) error {
+	s.mu.RLock()
+	enabled := s.config.AudioEnabled
+	segmentSeconds := s.config.AudioSegmentSeconds
+	whisperURL := s.config.WhisperURL
+	s.mu.RUnlock()
+	if !enabled {
+		return nil
+	}
+	if segmentSeconds <= 0 {
+		segmentSeconds = 30
+	}
+
+	if err := extract.ExtractAudioForVideo(videoPath, s.framesRoot, segmentSeconds); err != nil {
+		return fmt.Errorf("extract audio: %w", err)
+	}
+
+	segments, err := listAudioSegments(s.audioDirForVideo(videoPath))
+	if err != nil {
+		return fmt.Errorf("list audio segments: %w", err)
+	}
+	if len(segments) == 0 {
+		return nil
+	}
+
+	s.mu.RLock()
+	job := s.jobs[jobID]
+	video := s.videos[job.VideoID]
+	videoID, vPath := video.ID, video.Path
+	s.mu.RUnlock()
+
+	transcriber := transcribe.NewWhisperServerTranscriber(whisperURL)
+
+	for i, segmentPath := range segments {
+		select {
+		case <-cancelCh:
+			return context.Canceled
+		default:
+		}
+
+		offset := float64(i * segmentSeconds)
+		spans, err := transcriber.Transcribe(context.Background(), segmentPath)
+		if err != nil {
+			return fmt.Errorf("transcribe %s: %w", segmentPath, err)
+		}
+		for _, span := range spans {
+			err := s.vectorClient.UploadTranscriptSegment(context.Background(), TranscriptSegmentRequest{
+				VideoID:   videoID,
+				VideoPath: vPath,
+				Start:     offset + span.Start,
+				End:       offset + span.End,
+				Text:      span.Text,
+			})
+			if err != nil {
+				return fmt.Errorf("upload transcript segment: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// listAudioSegments returns the segmented WAV chunks written by
+// extract.ExtractAudioForVideo, in segment order, which also matches their
+// offset from the start of the video.
+func listAudioSegments(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var paths []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), "segment_") {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, e.Name()))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
@@ -0,0 +1,71 @@
+package daemon
+
+import (
+	"sync"
+	"time"
+)
+
+// allJobsTopic is the broker key subscribers use to receive events for every job.
+const allJobsTopic = "*"
+
+// JobEvent describes a single progress update pushed to job stream subscribers.
+type JobEvent struct {
+	JobID               string    `json:"job_id"`
+	VideoID             string    `json:"video_id"`
+	Status              string    `json:"status"`
+	Progress            float64   `json:"progress"`
+	FramesExtracted     int       `json:"frames_extracted"`
+	FramesUploaded      int       `json:"frames_uploaded"`
+	TotalFramesExpected int       `json:"total_frames_expected"`
+	LastError           *string   `json:"last_error,omitempty"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// jobBroker fans out JobEvents to subscribers, keyed by job ID, with a wildcard
+// topic for clients that want updates across every job.
+type jobBroker struct {
+	mu   sync.Mutex
+	subs map[string]map[chan JobEvent]struct{}
+}
+
+func newJobBroker() *jobBroker {
+	return &jobBroker{subs: make(map[string]map[chan JobEvent]struct{})}
+}
+
+// subscribe registers a buffered channel for the given topic (a job ID or
+// allJobsTopic) and returns it along with a function to unsubscribe.
+func (b *jobBroker) subscribe(topic string) (<-chan JobEvent, func()) {
+	ch := make(chan JobEvent, 16)
+	b.mu.Lock()
+	if b.subs[topic] == nil {
+		b.subs[topic] = make(map[chan JobEvent]struct{})
+	}
+	b.subs[topic][ch] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		delete(b.subs[topic], ch)
+		if len(b.subs[topic]) == 0 {
+			delete(b.subs, topic)
+		}
+		b.mu.Unlock()
+	}
+	return ch, unsubscribe
+}
+
+// publish delivers event to subscribers of jobID and to wildcard subscribers.
+// Sends are non-blocking: a slow or stalled subscriber drops events rather
+// than stalling the caller, which typically holds Server.mu.
+func (b *jobBroker) publish(jobID string, event JobEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, topic := range [...]string{jobID, allJobsTopic} {
+		for ch := range b.subs[topic] {
+			select {
+			case ch <- event:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"semanticvideo/internal/cloudupload"
+	"semanticvideo/internal/extract"
+)
+
+func (s *Server) uploadStatePath(framesDir string) string {
+	return filepath.Join(framesDir, ".upload_state.json")
+}
+
+// loadFrameTimestamps reads the frame_index.json sidecar, if any, mapping
+// each frame's file name to its true timestamp in seconds. Videos extracted
+// before chunk1-3 (or with no sidecar for some other reason) return nil, and
+// callers fall back to a zero timestamp rather than failing the upload.
+func loadFrameTimestamps(framesDir string) map[string]float64 {
+	data, err := os.ReadFile(filepath.Join(framesDir, "frame_index.json"))
+	if err != nil {
+		return nil
+	}
+	var entries []extract.FrameIndexEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil
+	}
+	out := make(map[string]float64, len(entries))
+	for _, e := range entries {
+		out[e.Path] = float64(e.TimestampMs) / 1000
+	}
+	return out
+}
+
+// batchFramesIntoParts splits framePaths into fixed-size, stably-numbered
+// parts. Part numbers must stay stable across runs for resume to work, which
+// holds here since framePaths is always the sorted output of listFrameFiles.
+func batchFramesIntoParts(framePaths []string, timestamps map[string]float64, batchSize int) [][]cloudupload.FrameRef {
+	var parts [][]cloudupload.FrameRef
+	for i := 0; i < len(framePaths); i += batchSize {
+		end := i + batchSize
+		if end > len(framePaths) {
+			end = len(framePaths)
+		}
+		batch := make([]cloudupload.FrameRef, 0, end-i)
+		for j := i; j < end; j++ {
+			path := framePaths[j]
+			batch = append(batch, cloudupload.FrameRef{
+				FrameNumber: j + 1,
+				FilePath:    path,
+				Timestamp:   timestamps[filepath.Base(path)],
+			})
+		}
+		parts = append(parts, batch)
+	}
+	return parts
+}
+
+// syncToCloud pushes every extracted frame for a video to the cloud backend
+// configured via Config.CloudBaseURL, in UploadBatchSize-frame parts
+// uploaded by a bounded pool of UploadConcurrency workers. It is a no-op
+// until a cloud access token has been stored via handleCloudAuth. Progress
+// is persisted to a per-video .upload_state.json sidecar after every part,
+// so a daemon restart resumes from the last acked part instead of
+// re-uploading the whole video.
+func (s *Server) syncToCloud(jobID, framesDir string, cancelCh <-chan struct{}) error {
+	s.mu.RLock()
+	token := s.cloud.AccessToken
+	baseURL := s.config.CloudBaseURL
+	batchSize := s.config.UploadBatchSize
+	concurrency := s.config.UploadConcurrency
+	job := s.jobs[jobID]
+	video := s.videos[job.VideoID]
+	videoID := video.ID
+	s.mu.RUnlock()
+
+	if token == "" {
+		return nil
+	}
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	framePaths, err := listFrameFiles(framesDir)
+	if err != nil {
+		return fmt.Errorf("list frames for cloud sync: %w", err)
+	}
+	if len(framePaths) == 0 {
+		return nil
+	}
+
+	statePath := s.uploadStatePath(framesDir)
+	state, err := cloudupload.LoadState(statePath)
+	if err != nil {
+		return fmt.Errorf("load upload state: %w", err)
+	}
+
+	uploader := cloudupload.NewUploader(baseURL, token, s.cloudLimiter.Load())
+
+	if state == nil || state.Done {
+		uploadID, err := uploader.CreateSession(context.Background(), videoID)
+		if err != nil {
+			return fmt.Errorf("create cloud upload session: %w", err)
+		}
+		state = &cloudupload.UploadState{
+			VideoID:        videoID,
+			UploadID:       uploadID,
+			CompletedParts: make(map[int]string),
+		}
+		if err := cloudupload.SaveState(statePath, state); err != nil {
+			return fmt.Errorf("save upload state: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	s.cloudSessions[jobID] = state.UploadID
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.cloudSessions, jobID)
+		s.mu.Unlock()
+	}()
+
+	timestamps := loadFrameTimestamps(framesDir)
+	parts := batchFramesIntoParts(framePaths, timestamps, batchSize)
+
+	remaining := len(parts) - len(state.CompletedParts)
+	if remaining < 0 {
+		remaining = 0
+	}
+	s.setPendingParts(remaining)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-cancelCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var stateMu sync.Mutex
+	partCh := make(chan int)
+	var wg sync.WaitGroup
+	var firstErr error
+	var errOnce sync.Once
+
+	if concurrency > len(parts) {
+		concurrency = len(parts)
+	}
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partNum := range partCh {
+				frames := parts[partNum]
+				err := cloudupload.RetryWithBackoff(ctx, 5, 500*time.Millisecond, func() error {
+					etag, err := uploader.UploadPart(ctx, state.UploadID, partNum, frames)
+					if err != nil {
+						return err
+					}
+					stateMu.Lock()
+					state.CompletedParts[partNum] = etag
+					saveErr := cloudupload.SaveState(statePath, state)
+					stateMu.Unlock()
+					return saveErr
+				})
+				if err != nil {
+					errOnce.Do(func() { firstErr = err })
+					cancel()
+					continue
+				}
+				s.decrementPendingParts()
+			}
+		}()
+	}
+
+dispatch:
+	for partNum := range parts {
+		stateMu.Lock()
+		_, done := state.CompletedParts[partNum]
+		stateMu.Unlock()
+		if done {
+			continue
+		}
+		select {
+		case partCh <- partNum:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(partCh)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	select {
+	case <-cancelCh:
+		return context.Canceled
+	default:
+	}
+
+	etags := make(map[int]string, len(state.CompletedParts))
+	for k, v := range state.CompletedParts {
+		etags[k] = v
+	}
+	if err := uploader.Complete(context.Background(), state.UploadID, etags); err != nil {
+		return fmt.Errorf("complete cloud upload: %w", err)
+	}
+	state.Done = true
+	if err := cloudupload.SaveState(statePath, state); err != nil {
+		return fmt.Errorf("save upload state: %w", err)
+	}
+
+	now := time.Now().UTC()
+	s.mu.Lock()
+	s.cloud.Status.LastSuccessfulUpload = &now
+	s.cloud.Status.PendingParts = 0
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Server) setPendingParts(n int) {
+	s.mu.Lock()
+	s.cloud.Status.PendingParts = n
+	s.mu.Unlock()
+}
+
+func (s *Server) decrementPendingParts() {
+	s.mu.Lock()
+	if s.cloud.Status.PendingParts > 0 {
+		s.cloud.Status.PendingParts--
+	}
+	s.mu.Unlock()
+}
@@ -0,0 +1,136 @@
+package daemon
+
+import (
+	"math"
+	"sort"
+)
+
+// defaultMergeGapSeconds is used when a search request omits merge_gap_seconds.
+const defaultMergeGapSeconds = 3.0
+
+// MergedInterval is a span of nearby vectordb timestamp hits within one video
+// that have been stitched into a single clip, with a thumbnail frame number
+// computed from the video's frame rate.
+type MergedInterval struct {
+	Start          float64 `json:"start" example:"12.5"`
+	End            float64 `json:"end" example:"18.0"`
+	RelevanceScore float64 `json:"relevance_score" example:"0.82"`
+	ThumbnailFrame int     `json:"thumbnail_frame" example:"13"`
+	Modality       string  `json:"modality" example:"visual"`
+	Text           string  `json:"text,omitempty" example:"a dog catches a frisbee"`
+}
+
+// clusteredVideoResult is the search response shape after re-clustering a
+// vectordb video result's raw timestamps into merged intervals.
+type clusteredVideoResult struct {
+	VideoID           string           `json:"video_id"`
+	VideoPath         string           `json:"video_path"`
+	Intervals         []MergedInterval `json:"intervals"`
+	MaxRelevanceScore float64          `json:"max_relevance_score"`
+}
+
+// mergeTimestamps sorts raw vectordb timestamps by start time and merges any
+// whose gap to the previous interval's end is within gapSeconds, aggregating
+// the relevance scores of the merged spans with the requested function.
+// frameRate converts each merged interval's start time into a 1-based frame
+// number matching the numbering used when frames were extracted. This holds
+// for fps sampling only: scene and hybrid extraction number frames in the
+// order they were kept, not by time.
+func mergeTimestamps(timestamps []vectorTimestamp, gapSeconds float64, aggregate string, frameRate float64) []MergedInterval {
+	if len(timestamps) == 0 {
+		return nil
+	}
+	if gapSeconds < 0 {
+		gapSeconds = 0
+	}
+
+	sorted := make([]vectorTimestamp, len(timestamps))
+	copy(sorted, timestamps)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	type span struct {
+		start, end float64
+		scores     []float64
+	}
+
+	var spans []span
+	cur := span{start: sorted[0].Start, end: sorted[0].End, scores: []float64{sorted[0].RelevanceScore}}
+	for _, ts := range sorted[1:] {
+		if ts.Start-cur.end <= gapSeconds {
+			if ts.End > cur.end {
+				cur.end = ts.End
+			}
+			cur.scores = append(cur.scores, ts.RelevanceScore)
+			continue
+		}
+		spans = append(spans, cur)
+		cur = span{start: ts.Start, end: ts.End, scores: []float64{ts.RelevanceScore}}
+	}
+	spans = append(spans, cur)
+
+	intervals := make([]MergedInterval, 0, len(spans))
+	for _, sp := range spans {
+		intervals = append(intervals, MergedInterval{
+			Start:          sp.start,
+			End:            sp.end,
+			RelevanceScore: aggregateScores(sp.scores, aggregate),
+			ThumbnailFrame: int(sp.start*frameRate) + 1,
+			Modality:       "visual",
+		})
+	}
+	return intervals
+}
+
+// audioIntervals converts vectordb transcript hits into MergedIntervals.
+// Unlike visual timestamps, transcript segments are already discrete
+// semantic spans from the transcriber, so they are passed through as-is
+// rather than gap-merged.
+func audioIntervals(segments []vectorAudioSegment, frameRate float64) []MergedInterval {
+	intervals := make([]MergedInterval, 0, len(segments))
+	for _, seg := range segments {
+		intervals = append(intervals, MergedInterval{
+			Start:          seg.Start,
+			End:            seg.End,
+			RelevanceScore: seg.RelevanceScore,
+			ThumbnailFrame: int(seg.Start*frameRate) + 1,
+			Modality:       "audio",
+			Text:           seg.Text,
+		})
+	}
+	return intervals
+}
+
+// aggregateScores combines the relevance scores of timestamps merged into a
+// single interval. Unrecognized modes fall back to "max".
+func aggregateScores(scores []float64, mode string) float64 {
+	switch mode {
+	case "mean":
+		sum := 0.0
+		for _, s := range scores {
+			sum += s
+		}
+		return sum / float64(len(scores))
+	case "softmax":
+		maxScore := scores[0]
+		for _, s := range scores {
+			if s > maxScore {
+				maxScore = s
+			}
+		}
+		var weightSum, scoreSum float64
+		for _, s := range scores {
+			w := math.Exp(s - maxScore)
+			weightSum += w
+			scoreSum += w * s
+		}
+		return scoreSum / weightSum
+	default:
+		maxScore := scores[0]
+		for _, s := range scores {
+			if s > maxScore {
+				maxScore = s
+			}
+		}
+		return maxScore
+	}
+}
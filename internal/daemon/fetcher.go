@@ -0,0 +1,26 @@
+package daemon
+
+import "context"
+
+// VideoMeta describes a remote video as reported by a Fetcher, before it has
+// been downloaded and probed locally.
+type VideoMeta struct {
+	Title           string
+	DurationSeconds int
+	ChannelID       string
+}
+
+// Fetcher downloads a remote video source to a local file and enumerates the
+// videos published by a channel, so remote sources can be fed into the same
+// extraction pipeline used for locally discovered files.
+type Fetcher interface {
+	// FetchVideo downloads url to a local temp file and returns its path
+	// along with whatever metadata the source provides.
+	FetchVideo(ctx context.Context, url string) (localPath string, meta VideoMeta, err error)
+	// ListChannel returns the video IDs published by channelID, paging
+	// through pageToken, and the token to resume from on the next call.
+	ListChannel(ctx context.Context, channelID, pageToken string) (ids []string, nextPageToken string, err error)
+	// ListPlaylist returns the video IDs contained in playlistID, paging
+	// through pageToken, and the token to resume from on the next call.
+	ListPlaylist(ctx context.Context, playlistID, pageToken string) (ids []string, nextPageToken string, err error)
+}
@@ -2,6 +2,8 @@ package daemon
 
 import (
 	"net/http"
+
+	"semanticvideo/internal/ratelimit"
 )
 
 // handleHealth godoc
@@ -39,10 +41,22 @@ func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusOK, cfg)
 	case http.MethodPut:
 		var req struct {
-			FrameRate       *float64 `json:"frame_rate"`
-			FrameSize       *[2]int  `json:"frame_size"`
-			UploadBatchSize *int     `json:"upload_batch_size"`
-			CloudBaseURL    *string  `json:"cloud_base_url"`
+			FrameRate           *float64 `json:"frame_rate"`
+			FrameSize           *[2]int  `json:"frame_size"`
+			UploadBatchSize     *int     `json:"upload_batch_size"`
+			UploadConcurrency   *int     `json:"upload_concurrency"`
+			CloudBaseURL        *string  `json:"cloud_base_url"`
+			AudioEnabled        *bool    `json:"audio_enabled"`
+			AudioSegmentSeconds *int     `json:"audio_segment_seconds"`
+			WhisperURL          *string  `json:"whisper_url"`
+			SamplingMode        *string  `json:"sampling_mode"`
+			SceneThreshold      *float64 `json:"scene_threshold"`
+			MinIntervalSeconds  *float64 `json:"min_interval_seconds"`
+			MaxIntervalSeconds  *float64 `json:"max_interval_seconds"`
+			VectorDBRatePerSec  *float64 `json:"vector_db_rate_per_sec"`
+			VectorDBBurst       *int     `json:"vector_db_burst"`
+			CloudRatePerSec     *float64 `json:"cloud_rate_per_sec"`
+			CloudBurst          *int     `json:"cloud_burst"`
 		}
 		if err := decodeJSON(r, &req); err != nil {
 			writeError(w, http.StatusBadRequest, "invalid json payload")
@@ -58,9 +72,57 @@ func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
 		if req.UploadBatchSize != nil {
 			s.config.UploadBatchSize = *req.UploadBatchSize
 		}
+		if req.UploadConcurrency != nil {
+			s.config.UploadConcurrency = *req.UploadConcurrency
+		}
 		if req.CloudBaseURL != nil {
 			s.config.CloudBaseURL = *req.CloudBaseURL
 		}
+		if req.AudioEnabled != nil {
+			s.config.AudioEnabled = *req.AudioEnabled
+		}
+		if req.AudioSegmentSeconds != nil {
+			s.config.AudioSegmentSeconds = *req.AudioSegmentSeconds
+		}
+		if req.WhisperURL != nil {
+			s.config.WhisperURL = *req.WhisperURL
+		}
+		if req.SamplingMode != nil {
+			s.config.SamplingMode = *req.SamplingMode
+		}
+		if req.SceneThreshold != nil {
+			s.config.SceneThreshold = *req.SceneThreshold
+		}
+		if req.MinIntervalSeconds != nil {
+			s.config.MinIntervalSeconds = *req.MinIntervalSeconds
+		}
+		if req.MaxIntervalSeconds != nil {
+			s.config.MaxIntervalSeconds = *req.MaxIntervalSeconds
+		}
+		vectorDBLimitsChanged := false
+		if req.VectorDBRatePerSec != nil {
+			s.config.VectorDBRatePerSec = *req.VectorDBRatePerSec
+			vectorDBLimitsChanged = true
+		}
+		if req.VectorDBBurst != nil {
+			s.config.VectorDBBurst = *req.VectorDBBurst
+			vectorDBLimitsChanged = true
+		}
+		if vectorDBLimitsChanged {
+			s.vectorClient.SetLimiter(ratelimit.NewLimiter(s.config.VectorDBRatePerSec, s.config.VectorDBBurst))
+		}
+		cloudLimitsChanged := false
+		if req.CloudRatePerSec != nil {
+			s.config.CloudRatePerSec = *req.CloudRatePerSec
+			cloudLimitsChanged = true
+		}
+		if req.CloudBurst != nil {
+			s.config.CloudBurst = *req.CloudBurst
+			cloudLimitsChanged = true
+		}
+		if cloudLimitsChanged {
+			s.cloudLimiter.Store(ratelimit.NewLimiter(s.config.CloudRatePerSec, s.config.CloudBurst))
+		}
 		s.mu.Unlock()
 		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 	}
@@ -1,11 +1,16 @@
 package daemon
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"semanticvideo/internal/probe"
 )
 
 // handleFolders godoc
@@ -32,6 +37,7 @@ func (s *Server) handleFolders(w http.ResponseWriter, r *http.Request) {
 		var req struct {
 			Path      string `json:"path"`
 			Recursive bool   `json:"recursive"`
+			Watch     bool   `json:"watch"`
 		}
 		if err := decodeJSON(r, &req); err != nil {
 			writeError(w, http.StatusBadRequest, "invalid json payload")
@@ -58,11 +64,20 @@ func (s *Server) handleFolders(w http.ResponseWriter, r *http.Request) {
 			Path:      req.Path,
 			Recursive: req.Recursive,
 			Status:    "scanning",
+			Watch:     req.Watch,
 		}
 		s.folders[folderID] = folder
 		s.folderByPath[req.Path] = folderID
 		s.mu.Unlock()
 
+		s.persistFolder(folder)
+
+		if req.Watch {
+			if err := s.startFolderWatcher(folderID, req.Path, req.Recursive); err != nil {
+				log.Printf("start watcher for folder %s failed: %v", folderID, err)
+			}
+		}
+
 		go s.scanFolderAndIndex(folderID, req.Path, req.Recursive)
 
 		writeJSON(w, http.StatusOK, map[string]string{
@@ -72,16 +87,68 @@ func (s *Server) handleFolders(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleUpdateFolder godoc
+// @Summary Toggle folder watching
+// @Description Starts or stops an fsnotify watcher for newly added video files in a tracked folder, covering subdirectories when the folder is tracked recursively.
+// @Tags folders
+// @Accept json
+// @Produce json
+// @Param folderID path string true "Folder ID"
+// @Param request body UpdateFolderRequest true "Desired watch state"
+// @Success 200 {object} StatusResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /folders/{folderID} [put]
+func (s *Server) handleUpdateFolder(w http.ResponseWriter, r *http.Request) {
+	folderID := chi.URLParam(r, "folderID")
+
+	var req UpdateFolderRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json payload")
+		return
+	}
+
+	s.mu.Lock()
+	folder, ok := s.folders[folderID]
+	if !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "folder not found")
+		return
+	}
+	folder.Watch = req.Watch
+	if !req.Watch {
+		folder.WatchStatus = "stopped"
+	}
+	s.folders[folderID] = folder
+	s.mu.Unlock()
+
+	s.persistFolder(folder)
+
+	if req.Watch {
+		if err := s.startFolderWatcher(folderID, folder.Path, folder.Recursive); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	} else {
+		s.stopFolderWatcher(folderID)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
 func (s *Server) scanFolderAndIndex(folderID, root string, recursive bool) {
 	videoPaths, err := collectVideoPaths(root, recursive)
 	if err != nil {
 		log.Printf("scan folder %s failed: %v", root, err)
 		s.mu.Lock()
-		if f, ok := s.folders[folderID]; ok {
+		f, ok := s.folders[folderID]
+		if ok {
 			f.Status = "error"
 			s.folders[folderID] = f
 		}
 		s.mu.Unlock()
+		if ok {
+			s.persistFolder(f)
+		}
 		return
 	}
 
@@ -99,11 +166,15 @@ func (s *Server) scanFolderAndIndex(folderID, root string, recursive bool) {
 	}
 
 	s.mu.Lock()
-	if f, ok := s.folders[folderID]; ok {
+	f, ok := s.folders[folderID]
+	if ok {
 		f.Status = "scanned"
 		s.folders[folderID] = f
 	}
 	s.mu.Unlock()
+	if ok {
+		s.persistFolder(f)
+	}
 }
 
 func collectVideoPaths(root string, recursive bool) ([]string, error) {
@@ -151,9 +222,21 @@ func isVideoFileName(name string) bool {
 }
 
 func (s *Server) addVideoPath(path string) (string, bool, error) {
+	s.mu.RLock()
+	if id, ok := s.videoByPath[path]; ok {
+		s.mu.RUnlock()
+		return id, true, nil
+	}
+	s.mu.RUnlock()
+
+	meta, err := probe.Probe(context.Background(), path)
+	if err != nil {
+		log.Printf("probe %s failed: %v", path, err)
+	}
+
 	s.mu.Lock()
-	defer s.mu.Unlock()
 	if id, ok := s.videoByPath[path]; ok {
+		s.mu.Unlock()
 		return id, true, nil
 	}
 	videoID := newID("vid_")
@@ -162,7 +245,11 @@ func (s *Server) addVideoPath(path string) (string, bool, error) {
 		Path:        path,
 		IndexStatus: "pending",
 	}
+	applyProbeMetadata(video, meta, err)
 	s.videos[videoID] = video
 	s.videoByPath[path] = videoID
+	s.mu.Unlock()
+
+	s.persistVideo(video)
 	return videoID, false, nil
 }
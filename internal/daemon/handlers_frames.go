@@ -0,0 +1,59 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/go-chi/chi/v5"
+
+	"semanticvideo/internal/extract"
+)
+
+// handleVideoFrames godoc
+// @Summary List extracted frame timestamps
+// @Description Returns the index, true timestamp, and file path of every frame extracted for a video, read from its frame_index.json sidecar.
+// @Tags videos
+// @Produce json
+// @Param videoID path string true "Video ID"
+// @Success 200 {array} VideoFrameInfo
+// @Failure 404 {object} ErrorResponse
+// @Router /videos/{videoID}/frames [get]
+func (s *Server) handleVideoFrames(w http.ResponseWriter, r *http.Request) {
+	videoID := chi.URLParam(r, "videoID")
+	s.mu.RLock()
+	video, ok := s.videos[videoID]
+	var videoPath string
+	if ok {
+		videoPath = video.Path
+	}
+	s.mu.RUnlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "video not found")
+		return
+	}
+
+	indexPath := filepath.Join(s.framesDirForVideo(videoPath), "frame_index.json")
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			writeJSON(w, http.StatusOK, []VideoFrameInfo{})
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var entries []extract.FrameIndexEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		writeError(w, http.StatusInternalServerError, "corrupt frame index")
+		return
+	}
+
+	frames := make([]VideoFrameInfo, len(entries))
+	for i, e := range entries {
+		frames[i] = VideoFrameInfo{Index: e.Index, TimestampMs: e.TimestampMs, Path: e.Path}
+	}
+	writeJSON(w, http.StatusOK, frames)
+}
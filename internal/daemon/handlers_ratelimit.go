@@ -0,0 +1,17 @@
+package daemon
+
+import "net/http"
+
+// handleRateLimit godoc
+// @Summary Get rate limiter status
+// @Description Returns current token counts and cumulative wait/dropped-request counters for the vectordb and cloud upload limiters.
+// @Tags config
+// @Produce json
+// @Success 200 {object} RateLimitResponse
+// @Router /ratelimit [get]
+func (s *Server) handleRateLimit(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, RateLimitResponse{
+		VectorDB: s.vectorClient.RateLimitStats(),
+		Cloud:    s.cloudLimiter.Load().Stats(),
+	})
+}
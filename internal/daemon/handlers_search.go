@@ -2,20 +2,27 @@ package daemon
 
 import (
 	"net/http"
+	"sort"
 )
 
-// handleSearch proxies text queries to the vectordb service and returns timestamped results.
+// handleSearch godoc
+// @Summary Search indexed video frames and transcripts
+// @Description Proxies a text query to the vectordb service (through a short-lived cache) across the requested modalities, then merges nearby hits per video into clips sorted by time.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param request body SearchRequest true "Search query, modalities, and clustering options"
+// @Success 200 {object} SearchResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Router /search [post]
 func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
-	var req struct {
-		Query            string  `json:"query"`
-		TopK             int     `json:"top_k"`
-		ClusterThreshold float64 `json:"cluster_threshold"`
-	}
+	var req SearchRequest
 	if err := decodeJSON(r, &req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid json payload")
 		return
@@ -26,6 +33,34 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 	if req.ClusterThreshold <= 0 {
 		req.ClusterThreshold = 5.0
 	}
+	if req.MergeGapSeconds <= 0 {
+		req.MergeGapSeconds = defaultMergeGapSeconds
+	}
+	switch req.Aggregate {
+	case "", "max", "mean", "softmax":
+	default:
+		writeError(w, http.StatusBadRequest, "aggregate must be one of: max, mean, softmax")
+		return
+	}
+	if req.Aggregate == "" {
+		req.Aggregate = "max"
+	}
+	modalities := req.Modalities
+	if len(modalities) == 0 {
+		modalities = []string{"visual"}
+	}
+	wantVisual, wantAudio := false, false
+	for _, m := range modalities {
+		switch m {
+		case "visual":
+			wantVisual = true
+		case "audio":
+			wantAudio = true
+		default:
+			writeError(w, http.StatusBadRequest, "modalities must be one of: visual, audio")
+			return
+		}
+	}
 
 	client := s.vectorClient
 	if client == nil {
@@ -33,13 +68,66 @@ func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	results, err := client.SearchVideos(r.Context(), req.Query, req.TopK, req.ClusterThreshold)
-	if err != nil {
-		writeError(w, http.StatusBadGateway, err.Error())
-		return
+	byVideo := make(map[string]*clusteredVideoResult)
+	get := func(videoID, videoPath string) *clusteredVideoResult {
+		res, ok := byVideo[videoID]
+		if !ok {
+			res = &clusteredVideoResult{VideoID: videoID, VideoPath: videoPath}
+			byVideo[videoID] = res
+		}
+		return res
+	}
+
+	s.mu.RLock()
+	frameRate := s.config.FrameRate
+	s.mu.RUnlock()
+
+	if wantVisual {
+		results, err := s.searchVideosCached(r.Context(), req.Query, req.TopK, req.ClusterThreshold)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		for _, res := range results {
+			entry := get(res.VideoID, res.VideoPath)
+			entry.Intervals = append(entry.Intervals, mergeTimestamps(res.Timestamps, req.MergeGapSeconds, req.Aggregate, frameRate)...)
+			if res.MaxRelevanceScore > entry.MaxRelevanceScore {
+				entry.MaxRelevanceScore = res.MaxRelevanceScore
+			}
+		}
 	}
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"results": results,
-	})
+	if wantAudio {
+		results, err := client.SearchAudio(r.Context(), req.Query, req.TopK)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		for _, res := range results {
+			entry := get(res.VideoID, res.VideoPath)
+			entry.Intervals = append(entry.Intervals, audioIntervals(res.Segments, frameRate)...)
+			if res.MaxRelevanceScore > entry.MaxRelevanceScore {
+				entry.MaxRelevanceScore = res.MaxRelevanceScore
+			}
+		}
+	}
+
+	clustered := make([]clusteredVideoResult, 0, len(byVideo))
+	for _, entry := range byVideo {
+		sort.Slice(entry.Intervals, func(i, j int) bool { return entry.Intervals[i].Start < entry.Intervals[j].Start })
+		clustered = append(clustered, *entry)
+	}
+
+	writeJSON(w, http.StatusOK, SearchResponse{Results: clustered})
+}
+
+// frameRateForVideo returns the video's probed native frame rate when known,
+// falling back to the globally configured extraction frame rate.
+func (s *Server) frameRateForVideo(videoID string) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if video, ok := s.videos[videoID]; ok && video.NativeFrameRate > 0 {
+		return video.NativeFrameRate
+	}
+	return s.config.FrameRate
 }
@@ -0,0 +1,117 @@
+package daemon
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// handleSources godoc
+// @Summary Track or list remote video sources
+// @Description GET lists tracked sources; POST registers a YouTube video URL, playlist, or channel for ingest.
+// @Tags sources
+// @Accept json
+// @Produce json
+// @Param request body AddSourceRequest true "Video, playlist, or channel URL/ID to track"
+// @Success 200 {array} URLSource
+// @Success 200 {object} AddSourceResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /sources [get]
+// @Router /sources [post]
+func (s *Server) handleSources(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.RLock()
+		list := make([]URLSource, 0, len(s.sources))
+		for _, src := range s.sources {
+			list = append(list, src)
+		}
+		s.mu.RUnlock()
+		writeJSON(w, http.StatusOK, list)
+	case http.MethodPost:
+		var req AddSourceRequest
+		if err := decodeJSON(r, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid json payload")
+			return
+		}
+		if strings.TrimSpace(req.URL) == "" {
+			writeError(w, http.StatusBadRequest, "url is required")
+			return
+		}
+
+		sourceType, collectionID := classifySource(req.URL)
+		source := URLSource{
+			ID:        newID("src_"),
+			Type:      sourceType,
+			URL:       req.URL,
+			ChannelID: collectionID,
+			Status:    "polling",
+			CreatedAt: time.Now().UTC(),
+		}
+
+		s.mu.Lock()
+		s.sources[source.ID] = source
+		s.mu.Unlock()
+		s.persistSource(source)
+
+		switch sourceType {
+		case "channel", "playlist":
+			go s.pollCollectionSource(source)
+		default:
+			go func() {
+				if err := s.ingestRemoteVideo(source.ID, "", source.URL); err != nil {
+					log.Printf("ingest source %s failed: %v", source.ID, err)
+				}
+			}()
+		}
+
+		writeJSON(w, http.StatusOK, AddSourceResponse{
+			SourceID: source.ID,
+			Type:     source.Type,
+			Status:   source.Status,
+		})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// handleSourceVideos godoc
+// @Summary List videos ingested from a source
+// @Description Returns every video whose channel_id matches the channel or playlist tracked by sourceID, or the single video for a video-type source.
+// @Tags sources
+// @Produce json
+// @Param sourceID path string true "Source ID"
+// @Success 200 {array} Video
+// @Failure 404 {object} ErrorResponse
+// @Router /sources/{sourceID}/videos [get]
+func (s *Server) handleSourceVideos(w http.ResponseWriter, r *http.Request) {
+	sourceID := chi.URLParam(r, "sourceID")
+
+	s.mu.RLock()
+	source, ok := s.sources[sourceID]
+	if !ok {
+		s.mu.RUnlock()
+		writeError(w, http.StatusNotFound, "source not found")
+		return
+	}
+
+	var list []Video
+	for _, v := range s.videos {
+		switch source.Type {
+		case "channel", "playlist":
+			if v.ChannelID == source.ChannelID {
+				list = append(list, *v)
+			}
+		default:
+			if v.SourceURL == source.URL {
+				list = append(list, *v)
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	writeJSON(w, http.StatusOK, list)
+}
@@ -0,0 +1,166 @@
+package daemon
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+)
+
+// wsUpgrader upgrades job status polling clients to a WebSocket connection.
+// CheckOrigin mirrors the localhost/127.0.0.1 allowlist already applied to
+// the REST routes by the cors.Handler in Routes.
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(r *http.Request) bool { return wsOriginAllowed(r.Header.Get("Origin")) },
+}
+
+// wsOriginAllowed reports whether origin is allowed to open a WebSocket
+// connection. A missing Origin header (non-browser clients, same-origin
+// requests in some browsers) is allowed, matching net/http's default
+// same-origin assumption; any other origin must be http(s)://localhost or
+// http(s)://127.0.0.1 on any port, the same hosts cors.Handler allows.
+func wsOriginAllowed(origin string) bool {
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "http", "https":
+	default:
+		return false
+	}
+	host := u.Hostname()
+	return host == "localhost" || host == "127.0.0.1"
+}
+
+const wsHeartbeatInterval = 20 * time.Second
+
+// handleJobsStream godoc
+// @Summary Stream job updates
+// @Description Upgrades to a WebSocket and pushes a snapshot of every job followed by live progress events.
+// @Tags jobs
+// @Router /jobs/stream [get]
+func (s *Server) handleJobsStream(w http.ResponseWriter, r *http.Request) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("jobs stream upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	events, unsubscribe := s.broker.subscribe(allJobsTopic)
+	defer unsubscribe()
+
+	s.mu.RLock()
+	snapshot := make([]JobEvent, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		snapshot = append(snapshot, s.jobEventLocked(job))
+	}
+	s.mu.RUnlock()
+	for _, event := range snapshot {
+		if err := conn.WriteJSON(event); err != nil {
+			return
+		}
+	}
+
+	s.streamEvents(conn, events)
+}
+
+// handleJobStream godoc
+// @Summary Stream updates for a single job
+// @Description Upgrades to a WebSocket and pushes an initial snapshot plus live progress events for one job.
+// @Tags jobs
+// @Param jobID path string true "Job ID"
+// @Router /jobs/{jobID}/stream [get]
+func (s *Server) handleJobStream(w http.ResponseWriter, r *http.Request) {
+	jobID := chi.URLParam(r, "jobID")
+
+	s.mu.RLock()
+	job, ok := s.jobs[jobID]
+	var snapshot JobEvent
+	if ok {
+		snapshot = s.jobEventLocked(job)
+	}
+	s.mu.RUnlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "job not found")
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("job stream upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	events, unsubscribe := s.broker.subscribe(jobID)
+	defer unsubscribe()
+
+	if err := conn.WriteJSON(snapshot); err != nil {
+		return
+	}
+
+	s.streamEvents(conn, events)
+}
+
+// streamEvents forwards broker events to conn until the client disconnects,
+// sending periodic pings to detect dead connections in the meantime.
+func (s *Server) streamEvents(conn *websocket.Conn, events <-chan JobEvent) {
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(wsHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// jobEventLocked builds a JobEvent for job. Callers must hold s.mu.
+func (s *Server) jobEventLocked(job *Job) JobEvent {
+	event := JobEvent{
+		JobID:     job.ID,
+		VideoID:   job.VideoID,
+		Status:    job.Status,
+		Progress:  job.Progress,
+		UpdatedAt: job.UpdatedAt,
+	}
+	if video, ok := s.videos[job.VideoID]; ok {
+		event.FramesExtracted = video.FramesExtracted
+		event.FramesUploaded = video.FramesUploaded
+		event.TotalFramesExpected = video.TotalFramesExpected
+		event.LastError = video.LastError
+	}
+	return event
+}
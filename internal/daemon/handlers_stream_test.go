@@ -0,0 +1,24 @@
+package daemon
+
+import "testing"
+
+func TestWSOriginAllowed(t *testing.T) {
+	cases := []struct {
+		origin string
+		want   bool
+	}{
+		{origin: "", want: true},
+		{origin: "http://localhost:5173", want: true},
+		{origin: "http://127.0.0.1:5173", want: true},
+		{origin: "https://localhost:4343", want: true},
+		{origin: "http://evil.example.com", want: false},
+		{origin: "http://localhost.evil.example.com", want: false},
+		{origin: "not a url", want: false},
+	}
+
+	for _, tc := range cases {
+		if got := wsOriginAllowed(tc.origin); got != tc.want {
+			t.Errorf("wsOriginAllowed(%q) = %v, want %v", tc.origin, got, tc.want)
+		}
+	}
+}
@@ -43,24 +43,18 @@ func (s *Server) handleVideos(w http.ResponseWriter, r *http.Request) {
 			writeError(w, http.StatusBadRequest, "path is required")
 			return
 		}
-		s.mu.Lock()
-		if id, exists := s.videoByPath[req.Path]; exists {
-			s.mu.Unlock()
+		videoID, exists, err := s.addVideoPath(req.Path)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if exists {
 			writeJSON(w, http.StatusOK, map[string]string{
-				"video_id": id,
+				"video_id": videoID,
 				"status":   "already_exists",
 			})
 			return
 		}
-		videoID := newID("vid_")
-		video := &Video{
-			ID:          videoID,
-			Path:        req.Path,
-			IndexStatus: "pending",
-		}
-		s.videos[videoID] = video
-		s.videoByPath[req.Path] = videoID
-		s.mu.Unlock()
 
 		writeJSON(w, http.StatusOK, map[string]string{
 			"video_id": videoID,
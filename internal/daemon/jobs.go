@@ -4,14 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"math"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"semanticvideo/internal/cloudupload"
 	"semanticvideo/internal/extract"
+	"semanticvideo/internal/probe"
 )
 
 // startJob schedules a new simulated extraction job for a video.
@@ -51,6 +56,9 @@ func (s *Server) startJob(videoID string, reindex bool) (*Job, error) {
 	s.jobCancel[jobID] = cancelCh
 	s.mu.Unlock()
 
+	s.persistVideo(video)
+	s.persistNewJob(job)
+
 	go s.runJob(jobID, cancelCh)
 	return job, nil
 }
@@ -81,7 +89,20 @@ func (s *Server) cancelJob(videoID string) error {
 		v.LastError = &msg
 	}
 	delete(s.jobCancel, jobID)
+	uploadID, hasUpload := s.cloudSessions[jobID]
+	token := s.cloud.AccessToken
+	baseURL := s.config.CloudBaseURL
+	s.broker.publish(jobID, s.jobEventLocked(job))
 	s.mu.Unlock()
+
+	if hasUpload && token != "" {
+		limiter := s.cloudLimiter.Load()
+		go func() {
+			if err := cloudupload.NewUploader(baseURL, token, limiter).Abort(context.Background(), uploadID); err != nil {
+				log.Printf("abort cloud upload %s: %v", uploadID, err)
+			}
+		}()
+	}
 	return nil
 }
 
@@ -107,8 +128,12 @@ func (s *Server) runJob(jobID string, cancelCh <-chan struct{}) {
 	videoPath := video.Path
 	framesDir := s.framesDirForVideo(videoPath)
 	cfg := extract.Config{
-		FrameRate: s.config.FrameRate,
-		FrameSize: s.config.FrameSize,
+		FrameRate:          s.config.FrameRate,
+		FrameSize:          s.config.FrameSize,
+		SamplingMode:       s.config.SamplingMode,
+		SceneThreshold:     s.config.SceneThreshold,
+		MinIntervalSeconds: s.config.MinIntervalSeconds,
+		MaxIntervalSeconds: s.config.MaxIntervalSeconds,
 	}
 	expected := video.TotalFramesExpected
 	if expected == 0 {
@@ -124,6 +149,7 @@ func (s *Server) runJob(jobID string, cancelCh <-chan struct{}) {
 	job.UpdatedAt = now
 	video.IndexStatus = "extracting"
 	video.LastError = nil
+	s.broker.publish(jobID, s.jobEventLocked(job))
 	s.mu.Unlock()
 
 	if err := os.MkdirAll(s.framesRoot, 0o755); err != nil {
@@ -158,6 +184,22 @@ func (s *Server) runJob(jobID string, cancelCh <-chan struct{}) {
 				}
 				return
 			}
+			if err := s.extractAndIndexAudio(jobID, videoPath, cancelCh); err != nil {
+				if errors.Is(err, context.Canceled) {
+					s.markJobCancelled(jobID)
+				} else {
+					s.failJob(jobID, fmt.Errorf("index audio: %w", err))
+				}
+				return
+			}
+			if err := s.syncToCloud(jobID, framesDir, cancelCh); err != nil {
+				if errors.Is(err, context.Canceled) {
+					s.markJobCancelled(jobID)
+				} else {
+					s.failJob(jobID, fmt.Errorf("sync cloud: %w", err))
+				}
+				return
+			}
 			s.completeJob(jobID)
 			return
 		case <-ticker.C:
@@ -169,10 +211,35 @@ func (s *Server) runJob(jobID string, cancelCh <-chan struct{}) {
 	}
 }
 
-// estimateFrames returns a rough frame count based on duration and configured frame rate.
+// estimateFrames returns a rough frame count based on duration and configured
+// frame rate. In scene/hybrid sampling modes the number of frames depends on
+// shot boundaries in the footage, not duration x fps, so no a-priori
+// estimate is possible; TotalFramesExpected is instead computed lazily once
+// ffmpeg has actually run, from the number of frame files it wrote (see
+// indexFrames and refreshJobProgress).
 func (s *Server) estimateFrames(video *Video) int {
-	expected := int(math.Ceil(float64(video.DurationSeconds) * s.config.FrameRate))
-	return expected
+	switch s.config.SamplingMode {
+	case "scene", "hybrid":
+		return 0
+	default:
+		return int(math.Ceil(float64(video.DurationSeconds) * s.config.FrameRate))
+	}
+}
+
+// applyProbeMetadata copies ffprobe results onto video. If probing failed,
+// video is left without duration/stream metadata and estimateFrames falls
+// back to its previous all-zero estimate rather than blocking registration
+// on ffprobe being installed and working.
+func applyProbeMetadata(video *Video, meta probe.Metadata, probeErr error) {
+	if probeErr != nil {
+		return
+	}
+	video.DurationSeconds = meta.DurationSeconds
+	video.NativeFrameRate = meta.FrameRate
+	video.Width = meta.Width
+	video.Height = meta.Height
+	video.Codec = meta.Codec
+	video.Container = meta.Container
 }
 
 func (s *Server) framesDirForVideo(videoPath string) string {
@@ -202,10 +269,19 @@ func (s *Server) refreshJobProgress(jobID, framesDir string) error {
 	s.updateProgressLocked(video, job, true)
 	job.UpdatedAt = time.Now().UTC()
 	video.IndexStatus = "extracting"
+	s.broker.publish(jobID, s.jobEventLocked(job))
 	s.mu.Unlock()
 	return nil
 }
 
+// pendingFrame pairs a frame file with its 1-based position among every
+// extracted frame, so frame numbers and timestamps stay correct even when
+// earlier frames are skipped because the upload manifest already has them.
+type pendingFrame struct {
+	path        string
+	frameNumber int
+}
+
 func (s *Server) indexFrames(jobID, framesDir string, cancelCh <-chan struct{}) error {
 	framePaths, err := listFrameFiles(framesDir)
 	if err != nil {
@@ -218,45 +294,160 @@ func (s *Server) indexFrames(jobID, framesDir string, cancelCh <-chan struct{})
 		return fmt.Errorf("vectordb client missing")
 	}
 
+	manifest := loadUploadManifest(framesDir)
+	var pending []pendingFrame
+	uploaded := 0
+	for i, framePath := range framePaths {
+		if manifest.isUploaded(framePath) {
+			uploaded++
+			continue
+		}
+		pending = append(pending, pendingFrame{path: framePath, frameNumber: i + 1})
+	}
+
 	s.mu.Lock()
 	job := s.jobs[jobID]
 	video := s.videos[job.VideoID]
 	video.IndexStatus = "indexing"
-	video.FramesUploaded = 0
+	video.FramesUploaded = uploaded
 	video.FramesExtracted = len(framePaths)
 	video.TotalFramesExpected = len(framePaths)
 	s.updateProgressLocked(video, job, true)
+	s.broker.publish(jobID, s.jobEventLocked(job))
+	videoID, videoPath, frameRate := video.ID, video.Path, s.config.FrameRate
 	s.mu.Unlock()
 
-	for i, framePath := range framePaths {
+	if len(pending) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
 		select {
 		case <-cancelCh:
-			return context.Canceled
-		default:
+			cancel()
+		case <-ctx.Done():
 		}
+	}()
 
-		frameNumber := i + 1
-		timestamp := float64(frameNumber-1) / s.config.FrameRate
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-		_, err := s.vectorClient.UploadImage(ctx, UploadImageRequest{
-			FilePath:    framePath,
-			VideoID:     video.ID,
-			VideoPath:   video.Path,
-			FrameNumber: frameNumber,
-			Timestamp:   timestamp,
-			FrameRate:   s.config.FrameRate,
-		})
-		cancel()
-		if err != nil {
-			return err
-		}
+	batchSize := s.config.UploadBatchSize
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+	batches := batchPendingFrames(pending, batchSize, s.vectorClient.SupportsBatchUpload(ctx))
+
+	concurrency := s.config.UploadConcurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	if concurrency > len(batches) {
+		concurrency = len(batches)
+	}
+
+	batchCh := make(chan []pendingFrame)
+	var uploadedCount int64 = int64(uploaded)
+	var firstErr error
+	var errOnce sync.Once
+	var wg sync.WaitGroup
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batchCh {
+				if err := s.uploadFrameBatch(ctx, batch, videoID, videoPath, frameRate); err != nil {
+					errOnce.Do(func() { firstErr = err })
+					cancel()
+					continue
+				}
+				for _, frame := range batch {
+					manifest.markUploaded(frame.path)
+				}
+				newTotal := atomic.AddInt64(&uploadedCount, int64(len(batch)))
+				s.markFrameUploaded(jobID, int(newTotal), len(framePaths))
+			}
+		}()
+	}
 
-		s.markFrameUploaded(jobID, i+1, len(framePaths))
+dispatch:
+	for _, batch := range batches {
+		select {
+		case batchCh <- batch:
+		case <-ctx.Done():
+			break dispatch
+		}
 	}
+	close(batchCh)
+	wg.Wait()
 
+	if firstErr != nil {
+		return firstErr
+	}
+	select {
+	case <-cancelCh:
+		return context.Canceled
+	default:
+	}
 	return nil
 }
 
+// batchPendingFrames groups pending frames into upload batches. When the
+// vectordb does not support batch uploads, each frame becomes its own
+// single-item batch so the worker pool still fans out over UploadImage.
+func batchPendingFrames(pending []pendingFrame, batchSize int, useBatch bool) [][]pendingFrame {
+	if !useBatch {
+		batches := make([][]pendingFrame, len(pending))
+		for i, frame := range pending {
+			batches[i] = []pendingFrame{frame}
+		}
+		return batches
+	}
+	var batches [][]pendingFrame
+	for i := 0; i < len(pending); i += batchSize {
+		end := i + batchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		batches = append(batches, pending[i:end])
+	}
+	return batches
+}
+
+// uploadFrameBatch uploads a single batch, using the multi-frame endpoint for
+// batches of more than one frame and the plain single-frame upload otherwise.
+func (s *Server) uploadFrameBatch(ctx context.Context, batch []pendingFrame, videoID, videoPath string, frameRate float64) error {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	if len(batch) == 1 {
+		frame := batch[0]
+		_, err := s.vectorClient.UploadImage(ctx, UploadImageRequest{
+			FilePath:    frame.path,
+			VideoID:     videoID,
+			VideoPath:   videoPath,
+			FrameNumber: frame.frameNumber,
+			Timestamp:   float64(frame.frameNumber-1) / frameRate,
+			FrameRate:   frameRate,
+		})
+		return err
+	}
+
+	reqs := make([]UploadImageRequest, len(batch))
+	for i, frame := range batch {
+		reqs[i] = UploadImageRequest{
+			FilePath:    frame.path,
+			VideoID:     videoID,
+			VideoPath:   videoPath,
+			FrameNumber: frame.frameNumber,
+			Timestamp:   float64(frame.frameNumber-1) / frameRate,
+			FrameRate:   frameRate,
+		}
+	}
+	_, err := s.vectorClient.UploadImagesBatch(ctx, reqs)
+	return err
+}
+
 func (s *Server) markFrameUploaded(jobID string, uploaded, total int) {
 	now := time.Now().UTC()
 	s.mu.Lock()
@@ -278,7 +469,11 @@ func (s *Server) markFrameUploaded(jobID string, uploaded, total int) {
 	s.updateProgressLocked(video, job, true)
 	job.UpdatedAt = now
 	video.IndexStatus = "indexing"
+	s.broker.publish(jobID, s.jobEventLocked(job))
 	s.mu.Unlock()
+
+	s.persistVideo(video)
+	s.persistJobProgress(job)
 }
 
 func (s *Server) updateProgressLocked(video *Video, job *Job, clamp bool) {
@@ -323,7 +518,11 @@ func (s *Server) completeJob(jobID string) {
 	s.cloud.Status.Connected = s.cloud.AccessToken != ""
 	s.cloud.Status.PendingBatches = 0
 	s.cloud.Status.LastSuccessfulUpload = &now
+	s.broker.publish(jobID, s.jobEventLocked(job))
 	s.mu.Unlock()
+
+	s.persistVideo(video)
+	s.persistJobProgress(job)
 }
 
 func (s *Server) failJob(jobID string, err error) {
@@ -335,14 +534,22 @@ func (s *Server) failJob(jobID string, err error) {
 		s.mu.Unlock()
 		return
 	}
+	var video *Video
 	job.Status = "failed"
 	job.Progress = 0
 	job.UpdatedAt = now
-	if video, exists := s.videos[job.VideoID]; exists {
-		video.IndexStatus = "failed"
-		video.LastError = &msg
+	if v, exists := s.videos[job.VideoID]; exists {
+		v.IndexStatus = "failed"
+		v.LastError = &msg
+		video = v
 	}
+	s.broker.publish(jobID, s.jobEventLocked(job))
 	s.mu.Unlock()
+
+	if video != nil {
+		s.persistVideo(video)
+	}
+	s.persistJobProgress(job)
 }
 
 func (s *Server) markJobCancelled(jobID string) {
@@ -360,6 +567,7 @@ func (s *Server) markJobCancelled(jobID string) {
 			msg := "cancelled"
 			video.LastError = &msg
 		}
+		s.broker.publish(jobID, s.jobEventLocked(job))
 	}
 	s.mu.Unlock()
 }
@@ -3,24 +3,42 @@ package daemon
 import (
 	"errors"
 	"time"
+
+	"semanticvideo/internal/ratelimit"
 )
 
 // Config holds global frame extraction and upload settings.
 type Config struct {
-	FrameRate       float64 `json:"frame_rate" example:"1.0"`
-	FrameSize       [2]int  `json:"frame_size" swaggertype:"array,integer" example:"400,400"`
-	UploadBatchSize int     `json:"upload_batch_size" example:"50"`
-	CloudBaseURL    string  `json:"cloud_base_url" example:"https://api.example.com"`
-	CloudUserID     string  `json:"cloud_user_id" example:"user_123"`
-	CloudAuthStatus string  `json:"cloud_auth_status" example:"missing_token"`
+	FrameRate           float64 `json:"frame_rate" example:"1.0"`
+	FrameSize           [2]int  `json:"frame_size" swaggertype:"array,integer" example:"400,400"`
+	UploadBatchSize     int     `json:"upload_batch_size" example:"50"`
+	UploadConcurrency   int     `json:"upload_concurrency" example:"4"`
+	CloudBaseURL        string  `json:"cloud_base_url" example:"https://api.example.com"`
+	CloudUserID         string  `json:"cloud_user_id" example:"user_123"`
+	CloudAuthStatus     string  `json:"cloud_auth_status" example:"missing_token"`
+	VectorDBURL         string  `json:"vector_db_url" example:"http://localhost:8000"`
+	Stateless           bool    `json:"stateless" example:"false"`
+	AudioEnabled        bool    `json:"audio_enabled" example:"false"`
+	AudioSegmentSeconds int     `json:"audio_segment_seconds" example:"30"`
+	WhisperURL          string  `json:"whisper_url" example:"http://localhost:8080"`
+	SamplingMode        string  `json:"sampling_mode" example:"fps"`
+	SceneThreshold      float64 `json:"scene_threshold" example:"0.3"`
+	MinIntervalSeconds  float64 `json:"min_interval_seconds" example:"1.0"`
+	MaxIntervalSeconds  float64 `json:"max_interval_seconds" example:"10.0"`
+	VectorDBRatePerSec  float64 `json:"vector_db_rate_per_sec" example:"10"`
+	VectorDBBurst       int     `json:"vector_db_burst" example:"10"`
+	CloudRatePerSec     float64 `json:"cloud_rate_per_sec" example:"5"`
+	CloudBurst          int     `json:"cloud_burst" example:"5"`
 }
 
 // Folder represents a tracked folder to scan for videos.
 type Folder struct {
-	ID        string `json:"folder_id" example:"fld_abcd1234"`
-	Path      string `json:"path" example:"/videos"`
-	Recursive bool   `json:"recursive" example:"true"`
-	Status    string `json:"status" example:"scheduled"`
+	ID          string `json:"folder_id" example:"fld_abcd1234"`
+	Path        string `json:"path" example:"/videos"`
+	Recursive   bool   `json:"recursive" example:"true"`
+	Status      string `json:"status" example:"scheduled"`
+	Watch       bool   `json:"watch" example:"false"`
+	WatchStatus string `json:"watch_status,omitempty" example:"watching"`
 }
 
 // Video tracks a single video and its extraction progress.
@@ -28,6 +46,14 @@ type Video struct {
 	ID                  string     `json:"video_id" example:"vid_abcd1234"`
 	Path                string     `json:"path" example:"/videos/sample.mp4"`
 	DurationSeconds     int        `json:"duration_seconds,omitempty" example:"120"`
+	NativeFrameRate     float64    `json:"native_frame_rate,omitempty" example:"29.97"`
+	Width               int        `json:"width,omitempty" example:"1920"`
+	Height              int        `json:"height,omitempty" example:"1080"`
+	Codec               string     `json:"codec,omitempty" example:"h264"`
+	Container           string     `json:"container,omitempty" example:"mov,mp4,m4a,3gp,3g2,mj2"`
+	SourceType          string     `json:"source_type,omitempty" example:"youtube"`
+	SourceURL           string     `json:"source_url,omitempty" example:"https://www.youtube.com/watch?v=dQw4w9WgXcQ"`
+	ChannelID           string     `json:"channel_id,omitempty" example:"UCabcdef1234567890"`
 	IndexStatus         string     `json:"index_status" example:"indexing"`
 	FramesExtracted     int        `json:"frames_extracted" example:"80"`
 	FramesUploaded      int        `json:"frames_uploaded" example:"80"`
@@ -52,6 +78,7 @@ type CloudStatus struct {
 	Connected            bool       `json:"connected" example:"true"`
 	LastSuccessfulUpload *time.Time `json:"last_successful_upload" example:"2024-01-01T12:10:00Z"`
 	PendingBatches       int        `json:"pending_batches" example:"0"`
+	PendingParts         int        `json:"pending_parts" example:"0"`
 }
 
 type CloudState struct {
@@ -72,10 +99,22 @@ type HealthResponse struct {
 
 // ConfigUpdateRequest allows partial configuration updates.
 type ConfigUpdateRequest struct {
-	FrameRate       *float64 `json:"frame_rate" example:"2.0"`
-	FrameSize       *[2]int  `json:"frame_size" swaggertype:"array,integer" example:"640,480"`
-	UploadBatchSize *int     `json:"upload_batch_size" example:"100"`
-	CloudBaseURL    *string  `json:"cloud_base_url" example:"https://api.example.com"`
+	FrameRate           *float64 `json:"frame_rate" example:"2.0"`
+	FrameSize           *[2]int  `json:"frame_size" swaggertype:"array,integer" example:"640,480"`
+	UploadBatchSize     *int     `json:"upload_batch_size" example:"100"`
+	UploadConcurrency   *int     `json:"upload_concurrency" example:"8"`
+	CloudBaseURL        *string  `json:"cloud_base_url" example:"https://api.example.com"`
+	AudioEnabled        *bool    `json:"audio_enabled" example:"true"`
+	AudioSegmentSeconds *int     `json:"audio_segment_seconds" example:"30"`
+	WhisperURL          *string  `json:"whisper_url" example:"http://localhost:8080"`
+	SamplingMode        *string  `json:"sampling_mode" example:"scene"`
+	SceneThreshold      *float64 `json:"scene_threshold" example:"0.3"`
+	MinIntervalSeconds  *float64 `json:"min_interval_seconds" example:"1.0"`
+	MaxIntervalSeconds  *float64 `json:"max_interval_seconds" example:"10.0"`
+	VectorDBRatePerSec  *float64 `json:"vector_db_rate_per_sec" example:"10"`
+	VectorDBBurst       *int     `json:"vector_db_burst" example:"10"`
+	CloudRatePerSec     *float64 `json:"cloud_rate_per_sec" example:"5"`
+	CloudBurst          *int     `json:"cloud_burst" example:"5"`
 }
 
 // StatusResponse is a generic status wrapper.
@@ -87,6 +126,39 @@ type StatusResponse struct {
 type AddFolderRequest struct {
 	Path      string `json:"path" example:"/videos"`
 	Recursive bool   `json:"recursive" example:"true"`
+	Watch     bool   `json:"watch" example:"false"`
+}
+
+// UpdateFolderRequest toggles whether a tracked folder is watched for new files.
+type UpdateFolderRequest struct {
+	Watch bool `json:"watch" example:"true"`
+}
+
+// URLSource is a tracked remote video source: a single video URL, or a
+// channel or playlist ID that is periodically polled for new videos.
+type URLSource struct {
+	ID   string `json:"source_id" example:"src_abcd1234"`
+	Type string `json:"type" example:"channel"`
+	URL  string `json:"url" example:"https://www.youtube.com/watch?v=dQw4w9WgXcQ"`
+	// ChannelID holds the collection ID being polled: a channel ID for
+	// Type "channel", or a playlist ID for Type "playlist". Unused for a
+	// bare "video" source.
+	ChannelID     string    `json:"channel_id,omitempty" example:"UCabcdef1234567890"`
+	Status        string    `json:"status" example:"polling"`
+	LastPageToken string    `json:"-"`
+	CreatedAt     time.Time `json:"created_at" example:"2024-01-01T12:00:00Z"`
+}
+
+// AddSourceRequest registers a remote video, playlist, or channel for ingest.
+type AddSourceRequest struct {
+	URL string `json:"url" example:"https://www.youtube.com/watch?v=dQw4w9WgXcQ"`
+}
+
+// AddSourceResponse returns the tracked source ID.
+type AddSourceResponse struct {
+	SourceID string `json:"source_id" example:"src_abcd1234"`
+	Type     string `json:"type" example:"channel"`
+	Status   string `json:"status" example:"polling"`
 }
 
 // AddFolderResponse returns the tracked folder ID.
@@ -127,4 +199,37 @@ type CloudAuthRequest struct {
 	AccessToken string `json:"access_token" example:"token_abc123"`
 }
 
+// SearchRequest queries the vectordb for matching frames and controls how
+// nearby hits within a video are stitched into clips.
+type SearchRequest struct {
+	Query            string   `json:"query" example:"dog catching a frisbee"`
+	TopK             int      `json:"top_k" example:"5"`
+	ClusterThreshold float64  `json:"cluster_threshold" example:"5.0"`
+	MergeGapSeconds  float64  `json:"merge_gap_seconds" example:"3.0"`
+	Aggregate        string   `json:"aggregate" example:"max"`
+	Modalities       []string `json:"modalities" example:"visual,audio"`
+}
+
+// SearchResponse returns, per matching video, the clips merged from raw
+// vectordb timestamp hits.
+type SearchResponse struct {
+	Results []clusteredVideoResult `json:"results"`
+}
+
+// RateLimitResponse reports the current bucket state of the vectordb and
+// cloud upload rate limiters, so operators can size VectorDBRatePerSec,
+// VectorDBBurst, CloudRatePerSec, and CloudBurst.
+type RateLimitResponse struct {
+	VectorDB ratelimit.Stats `json:"vector_db"`
+	Cloud    ratelimit.Stats `json:"cloud"`
+}
+
+// VideoFrameInfo describes one extracted frame's position in a video, read
+// from the frame_index.json sidecar written by extract.ExtractFramesForVideo.
+type VideoFrameInfo struct {
+	Index       int    `json:"index" example:"1"`
+	TimestampMs int64  `json:"timestamp_ms" example:"1501"`
+	Path        string `json:"path" example:"frame_00001.jpg"`
+}
+
 var errNotFound = errors.New("not found")
@@ -0,0 +1,215 @@
+package daemon
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"semanticvideo/internal/store"
+)
+
+// persistTimeout bounds each best-effort write-through to the backing store so
+// a slow database never stalls the in-memory request path for long.
+const persistTimeout = 5 * time.Second
+
+func videoToRecord(v *Video) store.VideoRecord {
+	return store.VideoRecord{
+		ID:                  v.ID,
+		Path:                v.Path,
+		DurationSeconds:     v.DurationSeconds,
+		NativeFrameRate:     v.NativeFrameRate,
+		Width:               v.Width,
+		Height:              v.Height,
+		Codec:               v.Codec,
+		Container:           v.Container,
+		SourceType:          v.SourceType,
+		SourceURL:           v.SourceURL,
+		ChannelID:           v.ChannelID,
+		IndexStatus:         v.IndexStatus,
+		FramesExtracted:     v.FramesExtracted,
+		FramesUploaded:      v.FramesUploaded,
+		TotalFramesExpected: v.TotalFramesExpected,
+		LastIndexedAt:       v.LastIndexedAt,
+		LastError:           v.LastError,
+	}
+}
+
+func jobToRecord(j *Job) store.JobRecord {
+	return store.JobRecord{
+		ID:        j.ID,
+		VideoID:   j.VideoID,
+		Type:      j.Type,
+		Status:    j.Status,
+		Progress:  j.Progress,
+		CreatedAt: j.CreatedAt,
+		UpdatedAt: j.UpdatedAt,
+	}
+}
+
+func folderToRecord(f Folder) store.FolderRecord {
+	return store.FolderRecord{
+		ID:          f.ID,
+		Path:        f.Path,
+		Recursive:   f.Recursive,
+		Status:      f.Status,
+		Watch:       f.Watch,
+		WatchStatus: f.WatchStatus,
+	}
+}
+
+func sourceToRecord(src URLSource) store.SourceRecord {
+	return store.SourceRecord{
+		ID:            src.ID,
+		Type:          src.Type,
+		URL:           src.URL,
+		ChannelID:     src.ChannelID,
+		Status:        src.Status,
+		LastPageToken: src.LastPageToken,
+		CreatedAt:     src.CreatedAt,
+	}
+}
+
+// persistVideo writes video through to the backing store, if one is configured.
+// Failures are logged rather than surfaced, since the in-memory map remains the
+// source of truth for the running process.
+func (s *Server) persistVideo(v *Video) {
+	if s.store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), persistTimeout)
+	defer cancel()
+	if err := s.store.UpsertVideo(ctx, videoToRecord(v)); err != nil {
+		log.Printf("persist video %s failed: %v", v.ID, err)
+	}
+}
+
+func (s *Server) persistNewJob(j *Job) {
+	if s.store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), persistTimeout)
+	defer cancel()
+	if err := s.store.CreateJob(ctx, jobToRecord(j)); err != nil {
+		log.Printf("persist job %s failed: %v", j.ID, err)
+	}
+}
+
+func (s *Server) persistJobProgress(j *Job) {
+	if s.store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), persistTimeout)
+	defer cancel()
+	if err := s.store.UpdateJobProgress(ctx, jobToRecord(j)); err != nil {
+		log.Printf("persist job progress %s failed: %v", j.ID, err)
+	}
+}
+
+func (s *Server) persistFolder(f Folder) {
+	if s.store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), persistTimeout)
+	defer cancel()
+	if err := s.store.UpsertFolder(ctx, folderToRecord(f)); err != nil {
+		log.Printf("persist folder %s failed: %v", f.ID, err)
+	}
+}
+
+func (s *Server) persistSource(src URLSource) {
+	if s.store == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), persistTimeout)
+	defer cancel()
+	if err := s.store.UpsertSource(ctx, sourceToRecord(src)); err != nil {
+		log.Printf("persist source %s failed: %v", src.ID, err)
+	}
+}
+
+// reconcileFromStore loads persisted videos and folders into the in-process
+// cache, then resolves any job left "queued" or "running" by a prior daemon
+// instance: the extraction goroutine that owned it is gone, so it is marked
+// failed rather than silently resumed.
+func (s *Server) reconcileFromStore() {
+	ctx, cancel := context.WithTimeout(context.Background(), persistTimeout)
+	defer cancel()
+
+	videos, err := s.store.ListVideos(ctx)
+	if err != nil {
+		log.Printf("reconcile: list videos failed: %v", err)
+	}
+	for _, rec := range videos {
+		rec := rec
+		video := &Video{
+			ID:                  rec.ID,
+			Path:                rec.Path,
+			DurationSeconds:     rec.DurationSeconds,
+			NativeFrameRate:     rec.NativeFrameRate,
+			Width:               rec.Width,
+			Height:              rec.Height,
+			Codec:               rec.Codec,
+			Container:           rec.Container,
+			SourceType:          rec.SourceType,
+			SourceURL:           rec.SourceURL,
+			ChannelID:           rec.ChannelID,
+			IndexStatus:         rec.IndexStatus,
+			FramesExtracted:     rec.FramesExtracted,
+			FramesUploaded:      rec.FramesUploaded,
+			TotalFramesExpected: rec.TotalFramesExpected,
+			LastIndexedAt:       rec.LastIndexedAt,
+			LastError:           rec.LastError,
+		}
+		s.videos[video.ID] = video
+		s.videoByPath[video.Path] = video.ID
+	}
+
+	folders, err := s.store.ListFolders(ctx)
+	if err != nil {
+		log.Printf("reconcile: list folders failed: %v", err)
+	}
+	for _, rec := range folders {
+		folder := Folder{ID: rec.ID, Path: rec.Path, Recursive: rec.Recursive, Status: rec.Status, Watch: rec.Watch}
+		s.folders[folder.ID] = folder
+		s.folderByPath[folder.Path] = folder.ID
+		if folder.Watch {
+			if err := s.startFolderWatcher(folder.ID, folder.Path, folder.Recursive); err != nil {
+				log.Printf("reconcile: start watcher for folder %s failed: %v", folder.ID, err)
+			}
+		}
+	}
+
+	sources, err := s.store.ListSources(ctx)
+	if err != nil {
+		log.Printf("reconcile: list sources failed: %v", err)
+	}
+	for _, rec := range sources {
+		s.sources[rec.ID] = URLSource{
+			ID:            rec.ID,
+			Type:          rec.Type,
+			URL:           rec.URL,
+			ChannelID:     rec.ChannelID,
+			Status:        rec.Status,
+			LastPageToken: rec.LastPageToken,
+			CreatedAt:     rec.CreatedAt,
+		}
+	}
+
+	pending, err := s.store.ListPendingJobs(ctx)
+	if err != nil {
+		log.Printf("reconcile: list pending jobs failed: %v", err)
+	}
+	for _, rec := range pending {
+		now := time.Now().UTC()
+		rec.Status = "failed"
+		rec.UpdatedAt = now
+		if err := s.store.UpdateJobProgress(ctx, rec); err != nil {
+			log.Printf("reconcile: mark job %s failed: %v", rec.ID, err)
+		}
+		if video, ok := s.videos[rec.VideoID]; ok {
+			video.IndexStatus = "failed"
+			msg := "daemon restarted before job completed"
+			video.LastError = &msg
+		}
+	}
+}
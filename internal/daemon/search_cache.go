@@ -0,0 +1,109 @@
+package daemon
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// searchCacheTTL bounds how long a cached vectordb search result is reused
+// before the next matching query re-hits the service, so newly indexed
+// videos show up in search within a bounded delay.
+const searchCacheTTL = 30 * time.Second
+
+// searchCacheCapacity is the maximum number of distinct (query, topK,
+// clusterThreshold) keys retained at once; the least recently used entry is
+// evicted once the cache is full.
+const searchCacheCapacity = 256
+
+type searchCacheEntry struct {
+	key       string
+	results   []vectorVideoResult
+	expiresAt time.Time
+}
+
+// searchCache is an LRU cache with a per-entry TTL in front of
+// VectorDBClient.SearchVideos, so repeated or polled queries for the same
+// (query, topK, clusterThreshold) don't re-hit the vectordb service.
+type searchCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+func newSearchCache(capacity int, ttl time.Duration) *searchCache {
+	return &searchCache{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func searchCacheKey(query string, topK int, clusterThreshold float64) string {
+	return fmt.Sprintf("%s\x00%d\x00%g", query, topK, clusterThreshold)
+}
+
+func (c *searchCache) get(key string) ([]vectorVideoResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*searchCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.results, true
+}
+
+func (c *searchCache) set(key string, results []vectorVideoResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*searchCacheEntry).results = results
+		elem.Value.(*searchCacheEntry).expiresAt = time.Now().Add(c.ttl)
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	entry := &searchCacheEntry{key: key, results: results, expiresAt: time.Now().Add(c.ttl)}
+	elem := c.order.PushFront(entry)
+	c.entries[key] = elem
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest == nil {
+			break
+		}
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*searchCacheEntry).key)
+	}
+}
+
+// searchVideosCached serves SearchVideos results from the cache when a fresh
+// entry exists for the same (query, topK, clusterThreshold), falling back to
+// the vectordb service and populating the cache on a miss.
+func (s *Server) searchVideosCached(ctx context.Context, query string, topK int, clusterThreshold float64) ([]vectorVideoResult, error) {
+	key := searchCacheKey(query, topK, clusterThreshold)
+	if results, ok := s.searchCache.get(key); ok {
+		return results, nil
+	}
+
+	results, err := s.vectorClient.SearchVideos(ctx, query, topK, clusterThreshold)
+	if err != nil {
+		return nil, err
+	}
+	s.searchCache.set(key, results)
+	return results, nil
+}
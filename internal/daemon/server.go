@@ -1,43 +1,71 @@
 package daemon
 
 import (
+	"context"
+	"log"
 	"net/http"
 	"os"
 	"sync"
+	"sync/atomic"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
 	httpSwagger "github.com/swaggo/http-swagger"
+
+	"semanticvideo/internal/ratelimit"
+	"semanticvideo/internal/store"
 )
 
-// Server stores all in-memory state and exposes HTTP handlers.
+// Server stores all in-memory state and exposes HTTP handlers. When a Store is
+// configured, the maps below act as an in-process cache over it; otherwise
+// they are the only copy of the state and do not survive a restart.
 type Server struct {
-	mu           sync.RWMutex
-	config       Config
-	folders      map[string]Folder
-	videos       map[string]*Video
-	jobs         map[string]*Job
-	jobCancel    map[string]chan struct{}
-	folderByPath map[string]string
-	videoByPath  map[string]string
-	cloud        CloudState
-	framesRoot   string
-	vectorClient *VectorDBClient
-	stateless    bool
-	cleanupDirs  []string
-	cleanupOnce  sync.Once
+	mu            sync.RWMutex
+	config        Config
+	folders       map[string]Folder
+	videos        map[string]*Video
+	jobs          map[string]*Job
+	jobCancel     map[string]chan struct{}
+	folderByPath  map[string]string
+	videoByPath   map[string]string
+	cloud         CloudState
+	framesRoot    string
+	vectorClient  *VectorDBClient
+	stateless     bool
+	cleanupDirs   []string
+	cleanupOnce   sync.Once
+	broker        *jobBroker
+	store         store.Store
+	watchersMu    sync.Mutex
+	watchers      map[string]*folderWatcher
+	searchCache   *searchCache
+	sources       map[string]URLSource
+	fetcher       Fetcher
+	pollerStop    chan struct{}
+	cloudSessions map[string]string
+	cloudLimiter  atomic.Pointer[ratelimit.Limiter]
 }
 
 func NewServer() *Server {
 	cfg := Config{
-		FrameRate:       1.0,
-		FrameSize:       [2]int{384, 384},
-		UploadBatchSize: 50,
-		CloudBaseURL:    "https://api.example.com",
-		CloudUserID:     "user_123",
-		CloudAuthStatus: "missing_token",
-		VectorDBURL:     "http://localhost:8000",
-		Stateless:       false,
+		FrameRate:           1.0,
+		FrameSize:           [2]int{384, 384},
+		UploadBatchSize:     50,
+		UploadConcurrency:   4,
+		CloudBaseURL:        "https://api.example.com",
+		CloudUserID:         "user_123",
+		CloudAuthStatus:     "missing_token",
+		VectorDBURL:         "http://localhost:8000",
+		Stateless:           false,
+		AudioSegmentSeconds: 30,
+		SamplingMode:        "fps",
+		SceneThreshold:      0.3,
+		MinIntervalSeconds:  1.0,
+		MaxIntervalSeconds:  10.0,
+		VectorDBRatePerSec:  0,
+		VectorDBBurst:       0,
+		CloudRatePerSec:     0,
+		CloudBurst:          0,
 	}
 
 	framesRoot := os.Getenv("FRAMES_ROOT")
@@ -65,7 +93,7 @@ func NewServer() *Server {
 	cfg.VectorDBURL = vectorURL
 	cfg.Stateless = stateless
 
-	return &Server{
+	s := &Server{
 		config:       cfg,
 		folders:      make(map[string]Folder),
 		videos:       make(map[string]*Video),
@@ -78,11 +106,34 @@ func NewServer() *Server {
 				UserID: cfg.CloudUserID,
 			},
 		},
-		framesRoot:   framesRoot,
-		vectorClient: NewVectorDBClient(vectorURL),
-		stateless:    stateless,
-		cleanupDirs:  cleanupDirs,
+		framesRoot:    framesRoot,
+		vectorClient:  NewVectorDBClient(vectorURL),
+		stateless:     stateless,
+		cleanupDirs:   cleanupDirs,
+		broker:        newJobBroker(),
+		watchers:      make(map[string]*folderWatcher),
+		searchCache:   newSearchCache(searchCacheCapacity, searchCacheTTL),
+		sources:       make(map[string]URLSource),
+		fetcher:       NewYouTubeFetcher(os.Getenv("YOUTUBE_DATA_API_KEY"), os.TempDir()),
+		pollerStop:    make(chan struct{}),
+		cloudSessions: make(map[string]string),
+	}
+	s.vectorClient.SetLimiter(ratelimit.NewLimiter(cfg.VectorDBRatePerSec, cfg.VectorDBBurst))
+	s.cloudLimiter.Store(ratelimit.NewLimiter(cfg.CloudRatePerSec, cfg.CloudBurst))
+
+	if dsn := os.Getenv("DATABASE_URL"); dsn != "" && !stateless {
+		pgStore, err := store.NewPostgresStore(context.Background(), dsn)
+		if err != nil {
+			log.Printf("persistent store unavailable, continuing with in-memory state only: %v", err)
+		} else {
+			s.store = pgStore
+			s.reconcileFromStore()
+		}
 	}
+
+	go s.pollSources()
+
+	return s
 }
 
 // Routes returns the HTTP handler for all endpoints.
@@ -118,6 +169,7 @@ func (s *Server) Routes() http.Handler {
 	// Folders
 	r.MethodFunc(http.MethodGet, "/folders", s.handleFolders)
 	r.MethodFunc(http.MethodPost, "/folders", s.handleFolders)
+	r.MethodFunc(http.MethodPut, "/folders/{folderID}", s.handleUpdateFolder)
 
 	// Videos
 	r.MethodFunc(http.MethodGet, "/videos", s.handleVideos)
@@ -127,10 +179,20 @@ func (s *Server) Routes() http.Handler {
 		r.MethodFunc(http.MethodPost, "/extract", s.handleExtract)
 		r.MethodFunc(http.MethodPost, "/cancel", s.handleCancel)
 		r.MethodFunc(http.MethodGet, "/file", s.handleVideoFile)
+		r.MethodFunc(http.MethodGet, "/frames", s.handleVideoFrames)
 	})
 
 	// Jobs
 	r.MethodFunc(http.MethodGet, "/jobs", s.handleJobs)
+	r.MethodFunc(http.MethodGet, "/jobs/stream", s.handleJobsStream)
+	r.Route("/jobs/{jobID}", func(r chi.Router) {
+		r.MethodFunc(http.MethodGet, "/stream", s.handleJobStream)
+	})
+
+	// Remote sources
+	r.MethodFunc(http.MethodPost, "/sources", s.handleSources)
+	r.MethodFunc(http.MethodGet, "/sources", s.handleSources)
+	r.MethodFunc(http.MethodGet, "/sources/{sourceID}/videos", s.handleSourceVideos)
 
 	// Search proxy
 	r.MethodFunc(http.MethodPost, "/search", s.handleSearch)
@@ -140,11 +202,27 @@ func (s *Server) Routes() http.Handler {
 	r.MethodFunc(http.MethodGet, "/cloud/status", s.handleCloudStatus)
 	r.MethodFunc(http.MethodPost, "/cloud/auth", s.handleCloudAuth)
 
+	// Rate limiter introspection
+	r.MethodFunc(http.MethodGet, "/ratelimit", s.handleRateLimit)
+
 	return r
 }
 
-// Cleanup removes temporary data when stateless mode is enabled.
+// Cleanup removes temporary data when stateless mode is enabled and releases
+// the backing store, if one is configured.
 func (s *Server) Cleanup() {
+	close(s.pollerStop)
+
+	s.watchersMu.Lock()
+	for folderID, fw := range s.watchers {
+		fw.stop()
+		delete(s.watchers, folderID)
+	}
+	s.watchersMu.Unlock()
+
+	if s.store != nil {
+		s.store.Close()
+	}
 	if !s.stateless {
 		return
 	}
@@ -0,0 +1,230 @@
+package daemon
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"semanticvideo/internal/probe"
+)
+
+// youtubeChannelIDPattern matches a bare YouTube channel ID (as opposed to a
+// video URL), e.g. "UCabcdef1234567890ABCDEF".
+var youtubeChannelIDPattern = regexp.MustCompile(`^UC[0-9A-Za-z_-]{22}$`)
+
+// classifySource determines whether a POST /sources input is a channel or
+// playlist to poll or a single video to fetch once, and extracts the
+// collection ID from a bare channel ID, a youtube.com/channel/... URL, or a
+// video/playlist URL carrying a "list" query parameter.
+func classifySource(input string) (sourceType, collectionID string) {
+	trimmed := strings.TrimSpace(input)
+	if youtubeChannelIDPattern.MatchString(trimmed) {
+		return "channel", trimmed
+	}
+	if idx := strings.Index(trimmed, "/channel/"); idx != -1 {
+		rest := trimmed[idx+len("/channel/"):]
+		rest = strings.SplitN(rest, "/", 2)[0]
+		rest = strings.SplitN(rest, "?", 2)[0]
+		if youtubeChannelIDPattern.MatchString(rest) {
+			return "channel", rest
+		}
+	}
+	if parsed, err := url.Parse(trimmed); err == nil {
+		if playlistID := parsed.Query().Get("list"); playlistID != "" {
+			return "playlist", playlistID
+		}
+	}
+	return "video", ""
+}
+
+// addRemoteVideo registers a video downloaded from a remote source, probing
+// the downloaded file the same way addVideoPath does for locally discovered
+// files so duration and stream metadata are populated consistently.
+func (s *Server) addRemoteVideo(localPath, sourceType, sourceURL, channelID string) (string, error) {
+	meta, err := probe.Probe(context.Background(), localPath)
+	if err != nil {
+		log.Printf("probe %s failed: %v", localPath, err)
+	}
+
+	s.mu.Lock()
+	videoID := newID("vid_")
+	video := &Video{
+		ID:          videoID,
+		Path:        localPath,
+		SourceType:  sourceType,
+		SourceURL:   sourceURL,
+		ChannelID:   channelID,
+		IndexStatus: "pending",
+	}
+	applyProbeMetadata(video, meta, err)
+	s.videos[videoID] = video
+	s.videoByPath[localPath] = videoID
+	s.mu.Unlock()
+
+	s.persistVideo(video)
+	return videoID, nil
+}
+
+// ingestRemoteVideo downloads videoURL through fetcher, registers it with the
+// given source metadata, and starts extraction. Once the job reaches a
+// terminal status, the downloaded temp file is removed since, unlike a
+// locally discovered file, the daemon owns it.
+func (s *Server) ingestRemoteVideo(sourceID, channelID, videoURL string) error {
+	localPath, meta, err := s.fetcher.FetchVideo(context.Background(), videoURL)
+	if err != nil {
+		return err
+	}
+	if channelID == "" {
+		channelID = meta.ChannelID
+	}
+
+	videoID, err := s.addRemoteVideo(localPath, "youtube", videoURL, channelID)
+	if err != nil {
+		os.Remove(localPath)
+		return err
+	}
+
+	job, err := s.startJob(videoID, false)
+	if err != nil {
+		os.Remove(localPath)
+		return err
+	}
+
+	go s.removeAfterJob(job.ID, localPath)
+	return nil
+}
+
+// removeAfterJob waits for jobID to reach a terminal status and then removes
+// path, the downloaded temp file backing a remote video. It subscribes
+// before checking the job's current status (the same order handleJobsStream
+// uses) so a job that finishes between startJob returning and this goroutine
+// running is still caught, instead of leaking the goroutine and the file
+// forever waiting on an event the broker already dropped.
+func (s *Server) removeAfterJob(jobID, path string) {
+	events, unsubscribe := s.broker.subscribe(jobID)
+	defer unsubscribe()
+
+	s.mu.RLock()
+	job, ok := s.jobs[jobID]
+	status := ""
+	if ok {
+		status = job.Status
+	}
+	s.mu.RUnlock()
+	if isTerminalJobStatus(status) {
+		_ = os.Remove(path)
+		return
+	}
+
+	for event := range events {
+		if isTerminalJobStatus(event.Status) {
+			_ = os.Remove(path)
+			return
+		}
+	}
+}
+
+// isTerminalJobStatus reports whether status is a final job state that will
+// never transition again.
+func isTerminalJobStatus(status string) bool {
+	switch status {
+	case "done", "failed":
+		return true
+	default:
+		return false
+	}
+}
+
+// sourcePollInterval is how often channel and playlist sources are checked
+// for new videos.
+const sourcePollInterval = 5 * time.Minute
+
+// pollSources periodically polls every tracked channel and playlist source,
+// one page per source per tick, until Cleanup stops the server.
+func (s *Server) pollSources() {
+	ticker := time.NewTicker(sourcePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.pollerStop:
+			return
+		case <-ticker.C:
+			s.pollAllCollectionSources()
+		}
+	}
+}
+
+// videoBySourceURLExists reports whether a video has already been ingested
+// from sourceURL, so a re-poll of the same page (e.g. after a restart before
+// the page token advanced) doesn't re-download it.
+func (s *Server) videoBySourceURLExists(sourceURL string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, v := range s.videos {
+		if v.SourceURL == sourceURL {
+			return true
+		}
+	}
+	return false
+}
+
+// pollAllCollectionSources polls every tracked channel or playlist source
+// once. A bare video source has nothing to poll: it was already ingested
+// when it was added.
+func (s *Server) pollAllCollectionSources() {
+	s.mu.RLock()
+	collections := make([]URLSource, 0, len(s.sources))
+	for _, src := range s.sources {
+		if src.Type == "channel" || src.Type == "playlist" {
+			collections = append(collections, src)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, src := range collections {
+		s.pollCollectionSource(src)
+	}
+}
+
+// pollCollectionSource fetches one page of video IDs for src, starting from
+// its persisted page token, and ingests each one not already ingested. The
+// page token only advances once the page has been handed off, so a daemon
+// restart mid-poll re-lists the same page rather than skipping it. After the
+// last page the token is empty, so the next poll starts from the first page.
+func (s *Server) pollCollectionSource(src URLSource) {
+	var (
+		ids           []string
+		nextPageToken string
+		err           error
+	)
+	switch src.Type {
+	case "playlist":
+		ids, nextPageToken, err = s.fetcher.ListPlaylist(context.Background(), src.ChannelID, src.LastPageToken)
+	default:
+		ids, nextPageToken, err = s.fetcher.ListChannel(context.Background(), src.ChannelID, src.LastPageToken)
+	}
+	if err != nil {
+		log.Printf("poll source %s failed: %v", src.ID, err)
+		return
+	}
+
+	for _, videoID := range ids {
+		videoURL := YouTubeWatchURLPrefix + videoID
+		if s.videoBySourceURLExists(videoURL) {
+			continue
+		}
+		if err := s.ingestRemoteVideo(src.ID, src.ChannelID, videoURL); err != nil {
+			log.Printf("ingest %s from source %s failed: %v", videoURL, src.ID, err)
+		}
+	}
+
+	s.mu.Lock()
+	src.LastPageToken = nextPageToken
+	s.sources[src.ID] = src
+	s.mu.Unlock()
+	s.persistSource(src)
+}
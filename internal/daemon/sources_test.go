@@ -0,0 +1,131 @@
+package daemon
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestClassifySource(t *testing.T) {
+	cases := []struct {
+		name             string
+		input            string
+		wantType         string
+		wantCollectionID string
+	}{
+		{
+			name:             "bare channel id",
+			input:            "UC_x5XG1OV2P6uZZ5FSM9Ttw",
+			wantType:         "channel",
+			wantCollectionID: "UC_x5XG1OV2P6uZZ5FSM9Ttw",
+		},
+		{
+			name:             "channel url",
+			input:            "https://www.youtube.com/channel/UC_x5XG1OV2P6uZZ5FSM9Ttw/videos",
+			wantType:         "channel",
+			wantCollectionID: "UC_x5XG1OV2P6uZZ5FSM9Ttw",
+		},
+		{
+			name:             "playlist url",
+			input:            "https://www.youtube.com/playlist?list=PLabc123",
+			wantType:         "playlist",
+			wantCollectionID: "PLabc123",
+		},
+		{
+			name:             "video url with playlist in query",
+			input:            "https://www.youtube.com/watch?v=dQw4w9WgXcQ&list=PLabc123",
+			wantType:         "playlist",
+			wantCollectionID: "PLabc123",
+		},
+		{
+			name:             "plain video url",
+			input:            "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+			wantType:         "video",
+			wantCollectionID: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotType, gotCollectionID := classifySource(tc.input)
+			if gotType != tc.wantType || gotCollectionID != tc.wantCollectionID {
+				t.Errorf("classifySource(%q) = (%q, %q), want (%q, %q)",
+					tc.input, gotType, gotCollectionID, tc.wantType, tc.wantCollectionID)
+			}
+		})
+	}
+}
+
+func newTestServer() *Server {
+	return &Server{
+		jobs:   make(map[string]*Job),
+		videos: make(map[string]*Video),
+		broker: newJobBroker(),
+	}
+}
+
+func TestRemoveAfterJobAlreadyTerminal(t *testing.T) {
+	s := newTestServer()
+	jobID := "job_1"
+	s.jobs[jobID] = &Job{ID: jobID, Status: "done"}
+
+	tmp, err := os.CreateTemp("", "remove-after-job-*")
+	if err != nil {
+		t.Fatalf("create temp file: %v", err)
+	}
+	path := tmp.Name()
+	tmp.Close()
+
+	// Simulates the race the broker can't protect against: the job already
+	// reached a terminal status before removeAfterJob got a chance to
+	// subscribe, so there is no event left to receive.
+	done := make(chan struct{})
+	go func() {
+		s.removeAfterJob(jobID, path)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("removeAfterJob did not return for an already-terminal job")
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, stat err = %v", path, err)
+	}
+}
+
+func TestRemoveAfterJobWaitsForEvent(t *testing.T) {
+	s := newTestServer()
+	jobID := "job_2"
+	s.jobs[jobID] = &Job{ID: jobID, Status: "running"}
+
+	tmp, err := os.CreateTemp("", "remove-after-job-*")
+	if err != nil {
+		t.Fatalf("create temp file: %v", err)
+	}
+	path := tmp.Name()
+	tmp.Close()
+
+	done := make(chan struct{})
+	go func() {
+		s.removeAfterJob(jobID, path)
+		close(done)
+	}()
+
+	// Give removeAfterJob a moment to subscribe before publishing, the same
+	// way a real runJob goroutine would take time to reach a terminal status.
+	time.Sleep(10 * time.Millisecond)
+	s.broker.publish(jobID, JobEvent{JobID: jobID, Status: "done"})
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("removeAfterJob did not return after a terminal event was published")
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, stat err = %v", path, err)
+	}
+}
@@ -0,0 +1,57 @@
+package daemon
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const uploadManifestFileName = ".upload_manifest.json"
+
+// uploadManifest records which frames in a video's frames directory have
+// already been uploaded to the vectordb, so a re-run of the job (after a
+// crash, cancellation, or failure) can resume instead of re-uploading frames.
+type uploadManifest struct {
+	mu   sync.Mutex
+	path string
+	// Uploaded maps a frame file's base name to true once it has been
+	// acknowledged by the vectordb.
+	Uploaded map[string]bool `json:"uploaded"`
+}
+
+func loadUploadManifest(framesDir string) *uploadManifest {
+	m := &uploadManifest{
+		path:     filepath.Join(framesDir, uploadManifestFileName),
+		Uploaded: make(map[string]bool),
+	}
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		return m
+	}
+	_ = json.Unmarshal(data, m)
+	if m.Uploaded == nil {
+		m.Uploaded = make(map[string]bool)
+	}
+	return m
+}
+
+func (m *uploadManifest) isUploaded(framePath string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.Uploaded[filepath.Base(framePath)]
+}
+
+// markUploaded records framePath as uploaded and persists the manifest to
+// disk. Persist failures are swallowed: the worst case is a redundant
+// re-upload on the next resume, not data loss.
+func (m *uploadManifest) markUploaded(framePath string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Uploaded[filepath.Base(framePath)] = true
+	data, err := json.Marshal(m)
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(m.path, data, 0o644)
+}
@@ -12,13 +12,32 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
+
+	"semanticvideo/internal/ratelimit"
 )
 
 // VectorDBClient wraps simple HTTP calls to the vectordb service.
 type VectorDBClient struct {
 	baseURL string
 	http    *http.Client
+	limiter atomic.Pointer[ratelimit.Limiter]
+}
+
+// SetLimiter swaps the rate limiter applied to every outbound call. It is
+// safe to call concurrently with in-flight requests, which finish out under
+// whichever limiter they started with.
+func (c *VectorDBClient) SetLimiter(l *ratelimit.Limiter) {
+	c.limiter.Store(l)
+}
+
+// RateLimitStats returns the current limiter's bucket state, for GET /ratelimit.
+func (c *VectorDBClient) RateLimitStats() ratelimit.Stats {
+	if c == nil {
+		return ratelimit.Stats{}
+	}
+	return c.limiter.Load().Stats()
 }
 
 type UploadImageRequest struct {
@@ -48,6 +67,9 @@ func (c *VectorDBClient) UploadImage(ctx context.Context, req UploadImageRequest
 	if strings.TrimSpace(req.FilePath) == "" {
 		return "", fmt.Errorf("file path is required")
 	}
+	if err := c.limiter.Load().Wait(ctx); err != nil {
+		return "", err
+	}
 
 	file, err := os.Open(req.FilePath)
 	if err != nil {
@@ -112,6 +134,237 @@ func (c *VectorDBClient) UploadImage(ctx context.Context, req UploadImageRequest
 	return payload.ID, nil
 }
 
+// SupportsBatchUpload probes the vectordb's /capabilities endpoint and reports
+// whether it accepts the multi-frame batch upload request. Any error talking
+// to the endpoint is treated as "no", since callers always have the
+// single-frame UploadImage path to fall back to.
+func (c *VectorDBClient) SupportsBatchUpload(ctx context.Context) bool {
+	if c == nil || c.baseURL == "" {
+		return false
+	}
+	if err := c.limiter.Load().Wait(ctx); err != nil {
+		return false
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/capabilities", nil)
+	if err != nil {
+		return false
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	var payload struct {
+		BatchUpload bool `json:"batch_upload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return false
+	}
+	return payload.BatchUpload
+}
+
+// UploadImagesBatch streams multiple frames from a single video to the
+// vectordb /upload_images_batch endpoint in one multipart request, each frame
+// as its own "file" part with matching "frame_number"/"timestamp" fields.
+func (c *VectorDBClient) UploadImagesBatch(ctx context.Context, reqs []UploadImageRequest) ([]string, error) {
+	if c == nil {
+		return nil, fmt.Errorf("vectordb client not configured")
+	}
+	if c.baseURL == "" {
+		return nil, fmt.Errorf("vectordb base URL is empty")
+	}
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("at least one frame is required")
+	}
+	if err := c.limiter.Load().Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for i, req := range reqs {
+		if strings.TrimSpace(req.FilePath) == "" {
+			return nil, fmt.Errorf("file path is required")
+		}
+		file, err := os.Open(req.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("open frame: %w", err)
+		}
+		part, err := writer.CreateFormFile("file", filepath.Base(req.FilePath))
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("create form file: %w", err)
+		}
+		_, copyErr := io.Copy(part, file)
+		file.Close()
+		if copyErr != nil {
+			return nil, fmt.Errorf("copy frame: %w", copyErr)
+		}
+		_ = writer.WriteField(fmt.Sprintf("video_id.%d", i), req.VideoID)
+		_ = writer.WriteField(fmt.Sprintf("video_path.%d", i), req.VideoPath)
+		_ = writer.WriteField(fmt.Sprintf("frame_number.%d", i), strconv.Itoa(req.FrameNumber))
+		_ = writer.WriteField(fmt.Sprintf("timestamp.%d", i), strconv.FormatFloat(req.Timestamp, 'f', -1, 64))
+		_ = writer.WriteField(fmt.Sprintf("frame_rate.%d", i), strconv.FormatFloat(req.FrameRate, 'f', -1, 64))
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("finalize form: %w", err)
+	}
+
+	endpoint := c.baseURL + "/upload_images_batch"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("vectordb request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		errBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("vectordb batch upload failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(errBody)))
+	}
+
+	var payload struct {
+		IDs []string `json:"ids"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decode vectordb response: %w", err)
+	}
+	if len(payload.IDs) != len(reqs) {
+		return nil, fmt.Errorf("vectordb returned %d ids for %d frames", len(payload.IDs), len(reqs))
+	}
+	return payload.IDs, nil
+}
+
+// TranscriptSegmentRequest is one transcribed span of speech to push to the
+// vectordb's spoken-content index.
+type TranscriptSegmentRequest struct {
+	VideoID   string  `json:"video_id"`
+	VideoPath string  `json:"video_path"`
+	Start     float64 `json:"start"`
+	End       float64 `json:"end"`
+	Text      string  `json:"text"`
+	Modality  string  `json:"modality"`
+}
+
+// UploadTranscriptSegment sends one transcribed audio span to the vectordb's
+// /upload_text_segment endpoint tagged with modality "audio", so searches
+// can tell spoken-content hits apart from frame hits.
+func (c *VectorDBClient) UploadTranscriptSegment(ctx context.Context, req TranscriptSegmentRequest) error {
+	if c == nil {
+		return fmt.Errorf("vectordb client not configured")
+	}
+	if c.baseURL == "" {
+		return fmt.Errorf("vectordb base URL is empty")
+	}
+	req.Modality = "audio"
+	if err := c.limiter.Load().Wait(ctx); err != nil {
+		return err
+	}
+
+	buf, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	endpoint := c.baseURL + "/upload_text_segment"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(buf))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("vectordb request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		errBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("vectordb upload transcript segment failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(errBody)))
+	}
+	return nil
+}
+
+// vectorAudioSegment is one transcribed span returned by a vectordb audio search hit.
+type vectorAudioSegment struct {
+	Start          float64 `json:"start"`
+	End            float64 `json:"end"`
+	Text           string  `json:"text"`
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+type vectorAudioResult struct {
+	VideoID           string               `json:"video_id"`
+	VideoPath         string               `json:"video_path"`
+	Segments          []vectorAudioSegment `json:"segments"`
+	MaxRelevanceScore float64              `json:"max_relevance_score"`
+}
+
+// SearchAudio queries the vectordb's spoken-content index, returning
+// transcript segments per video instead of frame timestamps.
+func (c *VectorDBClient) SearchAudio(ctx context.Context, query string, topK int) ([]vectorAudioResult, error) {
+	if c == nil {
+		return nil, fmt.Errorf("vectordb client not configured")
+	}
+	if strings.TrimSpace(c.baseURL) == "" {
+		return nil, fmt.Errorf("vectordb base URL is empty")
+	}
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("query cannot be empty")
+	}
+	if topK <= 0 {
+		topK = 5
+	}
+	if err := c.limiter.Load().Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	endpoint := c.baseURL + "/search_audio"
+	body := map[string]interface{}{
+		"query":    query,
+		"top_k":    topK,
+		"modality": "audio",
+	}
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(buf))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vectordb request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		errBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("vectordb audio search failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(errBody)))
+	}
+
+	var payload struct {
+		Results []vectorAudioResult `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decode vectordb response: %w", err)
+	}
+	return payload.Results, nil
+}
+
 type vectorTimestamp struct {
 	Start          float64 `json:"start"`
 	End            float64 `json:"end"`
@@ -141,6 +394,9 @@ func (c *VectorDBClient) SearchVideos(ctx context.Context, query string, topK in
 	if clusterThreshold <= 0 {
 		clusterThreshold = 5.0
 	}
+	if err := c.limiter.Load().Wait(ctx); err != nil {
+		return nil, err
+	}
 
 	endpoint := c.baseURL + "/search_video"
 	body := map[string]interface{}{
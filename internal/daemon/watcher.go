@@ -0,0 +1,195 @@
+package daemon
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long a watched file must sit quiet before it is
+// ingested, so a file that is still being copied in isn't indexed mid-write.
+const watchDebounce = 2 * time.Second
+
+// folderWatcher watches one tracked folder for new or renamed video files and
+// feeds them into the same ingest path as a one-shot folder scan.
+type folderWatcher struct {
+	folderID  string
+	root      string
+	recursive bool
+	fsw       *fsnotify.Watcher
+	stopOnce  sync.Once
+	stopCh    chan struct{}
+
+	mu      sync.Mutex
+	pending map[string]*time.Timer
+}
+
+// startFolderWatcher registers an fsnotify watcher for root and stores it on
+// Server, registering recursive watches on every existing subdirectory when
+// recursive is true. Any previous watcher for folderID is stopped first.
+func (s *Server) startFolderWatcher(folderID, root string, recursive bool) error {
+	s.stopFolderWatcher(folderID)
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create watcher: %w", err)
+	}
+
+	fw := &folderWatcher{
+		folderID:  folderID,
+		root:      root,
+		recursive: recursive,
+		fsw:       fsw,
+		stopCh:    make(chan struct{}),
+		pending:   make(map[string]*time.Timer),
+	}
+	if err := fw.watchDirs(root, recursive); err != nil {
+		fsw.Close()
+		return fmt.Errorf("watch %s: %w", root, err)
+	}
+
+	s.watchersMu.Lock()
+	s.watchers[folderID] = fw
+	s.watchersMu.Unlock()
+
+	go fw.run(s)
+	s.setFolderWatchStatus(folderID, "watching")
+	return nil
+}
+
+// stopFolderWatcher stops and discards the watcher for folderID, if any.
+func (s *Server) stopFolderWatcher(folderID string) {
+	s.watchersMu.Lock()
+	fw, ok := s.watchers[folderID]
+	delete(s.watchers, folderID)
+	s.watchersMu.Unlock()
+	if ok {
+		fw.stop()
+	}
+}
+
+// watchDirs registers watches on root, and every subdirectory when recursive.
+func (fw *folderWatcher) watchDirs(root string, recursive bool) error {
+	if !recursive {
+		return fw.fsw.Add(root)
+	}
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return fw.fsw.Add(path)
+		}
+		return nil
+	})
+}
+
+func (fw *folderWatcher) run(s *Server) {
+	defer fw.fsw.Close()
+	for {
+		select {
+		case <-fw.stopCh:
+			return
+		case event, ok := <-fw.fsw.Events:
+			if !ok {
+				return
+			}
+			fw.handleEvent(s, event)
+		case err, ok := <-fw.fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("folder watcher %s error: %v", fw.folderID, err)
+			s.setFolderWatchStatus(fw.folderID, fmt.Sprintf("error: %v", err))
+		}
+	}
+}
+
+func (fw *folderWatcher) handleEvent(s *Server, event fsnotify.Event) {
+	if event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Rename) == 0 {
+		return
+	}
+
+	if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+		if fw.recursive && event.Op&fsnotify.Create != 0 {
+			if err := fw.fsw.Add(event.Name); err != nil {
+				log.Printf("folder watcher %s: watch new dir %s: %v", fw.folderID, event.Name, err)
+			}
+		}
+		return
+	}
+
+	if !isVideoFileName(filepath.Base(event.Name)) {
+		return
+	}
+
+	fw.debounce(event.Name, func() {
+		s.ingestWatchedFile(fw.folderID, event.Name)
+	})
+}
+
+// debounce (re)schedules fn to run after watchDebounce of quiet for path,
+// restarting the timer on every call so a burst of writes to the same file
+// only triggers one ingest after the writes stop.
+func (fw *folderWatcher) debounce(path string, fn func()) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	if t, ok := fw.pending[path]; ok {
+		t.Stop()
+	}
+	fw.pending[path] = time.AfterFunc(watchDebounce, func() {
+		fw.mu.Lock()
+		delete(fw.pending, path)
+		fw.mu.Unlock()
+		fn()
+	})
+}
+
+func (fw *folderWatcher) stop() {
+	fw.stopOnce.Do(func() {
+		close(fw.stopCh)
+		fw.mu.Lock()
+		for path, t := range fw.pending {
+			t.Stop()
+			delete(fw.pending, path)
+		}
+		fw.mu.Unlock()
+	})
+}
+
+// ingestWatchedFile registers a newly observed video file and starts
+// extraction for it, mirroring what scanFolderAndIndex does for each file
+// found during a one-shot scan.
+func (s *Server) ingestWatchedFile(folderID, path string) {
+	videoID, exists, err := s.addVideoPath(path)
+	if err != nil {
+		log.Printf("folder watcher %s: add video %s failed: %v", folderID, path, err)
+		return
+	}
+	if exists {
+		return
+	}
+	if _, err := s.startJob(videoID, false); err != nil {
+		log.Printf("folder watcher %s: start job for %s failed: %v", folderID, videoID, err)
+	}
+}
+
+// setFolderWatchStatus updates and persists the WatchStatus surfaced on the
+// folder for watcher errors and state transitions.
+func (s *Server) setFolderWatchStatus(folderID, status string) {
+	s.mu.Lock()
+	f, ok := s.folders[folderID]
+	if ok {
+		f.WatchStatus = status
+		s.folders[folderID] = f
+	}
+	s.mu.Unlock()
+	if ok {
+		s.persistFolder(f)
+	}
+}
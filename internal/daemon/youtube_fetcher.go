@@ -0,0 +1,252 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/kkdai/youtube/v2"
+)
+
+// youtubeDataAPIBaseURL is the YouTube Data API v3 endpoint used to list the
+// videos published by a channel.
+const youtubeDataAPIBaseURL = "https://www.googleapis.com/youtube/v3"
+
+// YouTubeWatchURLPrefix builds a canonical watch URL for a video ID returned
+// by ListChannel, for use as a Video's SourceURL.
+const YouTubeWatchURLPrefix = "https://www.youtube.com/watch?v="
+
+// YouTubeFetcher implements Fetcher against youtube.com: stream downloads go
+// through github.com/kkdai/youtube/v2, and channel enumeration goes through
+// the YouTube Data API's search endpoint.
+type YouTubeFetcher struct {
+	client  youtube.Client
+	apiKey  string
+	http    *http.Client
+	tempDir string
+}
+
+// NewYouTubeFetcher builds a YouTubeFetcher. apiKey may be empty, in which
+// case FetchVideo still works for single video URLs but ListChannel returns
+// an error, since channel enumeration requires the Data API.
+func NewYouTubeFetcher(apiKey, tempDir string) *YouTubeFetcher {
+	return &YouTubeFetcher{
+		apiKey:  apiKey,
+		http:    &http.Client{},
+		tempDir: tempDir,
+	}
+}
+
+// FetchVideo resolves videoURL, downloads the best available progressive
+// stream to a temp file under f.tempDir, and returns its path. The caller is
+// responsible for removing the file once extraction has consumed it.
+func (f *YouTubeFetcher) FetchVideo(ctx context.Context, videoURL string) (string, VideoMeta, error) {
+	video, err := f.client.GetVideoContext(ctx, videoURL)
+	if err != nil {
+		return "", VideoMeta{}, fmt.Errorf("resolve video: %w", err)
+	}
+	formats := video.Formats.AudioChannels(2)
+	if len(formats) == 0 {
+		// Fall back to every format rather than failing outright; plenty of
+		// progressive streams still carry a mono or no-audio-metadata track.
+		formats = video.Formats
+	}
+	if len(formats) == 0 {
+		return "", VideoMeta{}, fmt.Errorf("no downloadable formats for %s", videoURL)
+	}
+
+	stream, _, err := f.client.GetStreamContext(ctx, video, &formats[0])
+	if err != nil {
+		return "", VideoMeta{}, fmt.Errorf("open stream: %w", err)
+	}
+	defer stream.Close()
+
+	tmp, err := os.CreateTemp(f.tempDir, "yt-*.mp4")
+	if err != nil {
+		return "", VideoMeta{}, fmt.Errorf("create temp file: %w", err)
+	}
+	defer tmp.Close()
+
+	if _, err := io.Copy(tmp, stream); err != nil {
+		os.Remove(tmp.Name())
+		return "", VideoMeta{}, fmt.Errorf("download video: %w", err)
+	}
+
+	// *youtube.Video carries no channel ID field, so it has to come from a
+	// separate Data API lookup. Best-effort only: a missing API key or a
+	// lookup failure shouldn't fail a download that has already succeeded.
+	channelID, err := f.lookupChannelID(ctx, video.ID)
+	if err != nil {
+		log.Printf("lookup channel id for %s: %v", video.ID, err)
+	}
+
+	return tmp.Name(), VideoMeta{
+		Title:           video.Title,
+		DurationSeconds: int(video.Duration.Seconds()),
+		ChannelID:       channelID,
+	}, nil
+}
+
+// lookupChannelID resolves videoID's channel ID via the YouTube Data API's
+// videos endpoint. It returns an empty string without error when no API key
+// is configured, since FetchVideo must still work for single video URLs
+// without one.
+func (f *YouTubeFetcher) lookupChannelID(ctx context.Context, videoID string) (string, error) {
+	if f.apiKey == "" {
+		return "", nil
+	}
+
+	q := url.Values{}
+	q.Set("key", f.apiKey)
+	q.Set("id", videoID)
+	q.Set("part", "snippet")
+	endpoint := youtubeDataAPIBaseURL + "/videos?" + q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	resp, err := f.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("youtube data api request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("youtube data api failed (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var payload struct {
+		Items []struct {
+			Snippet struct {
+				ChannelID string `json:"channelId"`
+			} `json:"snippet"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("decode youtube data api response: %w", err)
+	}
+	if len(payload.Items) == 0 {
+		return "", nil
+	}
+	return payload.Items[0].Snippet.ChannelID, nil
+}
+
+// ListChannel pages through the videos uploaded by channelID using the
+// YouTube Data API's search endpoint, ordered newest first. pageToken
+// continues from the page after the one last returned, i.e. toward older
+// uploads.
+func (f *YouTubeFetcher) ListChannel(ctx context.Context, channelID, pageToken string) ([]string, string, error) {
+	if f.apiKey == "" {
+		return nil, "", fmt.Errorf("youtube data api key not configured")
+	}
+
+	q := url.Values{}
+	q.Set("key", f.apiKey)
+	q.Set("channelId", channelID)
+	q.Set("part", "id")
+	q.Set("order", "date")
+	q.Set("type", "video")
+	q.Set("maxResults", "50")
+	if pageToken != "" {
+		q.Set("pageToken", pageToken)
+	}
+	endpoint := youtubeDataAPIBaseURL + "/search?" + q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("create request: %w", err)
+	}
+	resp, err := f.http.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("youtube data api request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, "", fmt.Errorf("youtube data api failed (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var payload struct {
+		NextPageToken string `json:"nextPageToken"`
+		Items         []struct {
+			ID struct {
+				VideoID string `json:"videoId"`
+			} `json:"id"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, "", fmt.Errorf("decode youtube data api response: %w", err)
+	}
+
+	ids := make([]string, 0, len(payload.Items))
+	for _, item := range payload.Items {
+		if item.ID.VideoID != "" {
+			ids = append(ids, item.ID.VideoID)
+		}
+	}
+	return ids, payload.NextPageToken, nil
+}
+
+// ListPlaylist pages through the videos contained in playlistID using the
+// YouTube Data API's playlistItems endpoint, in playlist order. pageToken
+// continues from the page after the one last returned.
+func (f *YouTubeFetcher) ListPlaylist(ctx context.Context, playlistID, pageToken string) ([]string, string, error) {
+	if f.apiKey == "" {
+		return nil, "", fmt.Errorf("youtube data api key not configured")
+	}
+
+	q := url.Values{}
+	q.Set("key", f.apiKey)
+	q.Set("playlistId", playlistID)
+	q.Set("part", "contentDetails")
+	q.Set("maxResults", "50")
+	if pageToken != "" {
+		q.Set("pageToken", pageToken)
+	}
+	endpoint := youtubeDataAPIBaseURL + "/playlistItems?" + q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("create request: %w", err)
+	}
+	resp, err := f.http.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("youtube data api request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, "", fmt.Errorf("youtube data api failed (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var payload struct {
+		NextPageToken string `json:"nextPageToken"`
+		Items         []struct {
+			ContentDetails struct {
+				VideoID string `json:"videoId"`
+			} `json:"contentDetails"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, "", fmt.Errorf("decode youtube data api response: %w", err)
+	}
+
+	ids := make([]string, 0, len(payload.Items))
+	for _, item := range payload.Items {
+		if item.ContentDetails.VideoID != "" {
+			ids = append(ids, item.ContentDetails.VideoID)
+		}
+	}
+	return ids, payload.NextPageToken, nil
+}
+
+var _ Fetcher = (*YouTubeFetcher)(nil)
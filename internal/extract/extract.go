@@ -1,20 +1,52 @@
 package extract
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 
 	ffmpeg "github.com/u2takey/ffmpeg-go"
 )
 
+// Default scene-mode tuning, used when a Config leaves the corresponding
+// field at its zero value.
+const (
+	defaultSceneThreshold     = 0.3
+	defaultMinIntervalSeconds = 1.0
+	defaultMaxIntervalSeconds = 10.0
+)
+
 type Config struct {
 	FrameRate float64 `json:"frame_rate"`
 	FrameSize [2]int  `json:"frame_size"`
+
+	// SamplingMode selects how frames are chosen: "fps" (default) samples at
+	// a fixed rate, "scene" emits a frame only at detected shot boundaries,
+	// and "hybrid" does the same but guarantees at least one frame every
+	// MaxIntervalSeconds.
+	SamplingMode       string  `json:"sampling_mode"`
+	SceneThreshold     float64 `json:"scene_threshold"`
+	MinIntervalSeconds float64 `json:"min_interval_seconds"`
+	MaxIntervalSeconds float64 `json:"max_interval_seconds"`
+}
+
+// FrameIndexEntry records the true timestamp of one extracted frame. It is
+// written as a frame_index.json sidecar alongside the frame JPEGs so callers
+// can seek accurately even when frames aren't evenly spaced (scene/hybrid
+// sampling).
+type FrameIndexEntry struct {
+	Index       int    `json:"index"`
+	TimestampMs int64  `json:"timestamp_ms"`
+	Path        string `json:"path"`
 }
 
+var showinfoPTSTime = regexp.MustCompile(`pts_time:([0-9.]+)`)
+
 func ExtractFramesForVideo(inputPath, framesRoot string, cfg Config) error {
 	base := filepath.Base(inputPath)
 	name := strings.TrimSuffix(base, filepath.Ext(base))
@@ -25,26 +57,225 @@ func ExtractFramesForVideo(inputPath, framesRoot string, cfg Config) error {
 		return fmt.Errorf("create frames dir: %w", err)
 	}
 
-	fpsStr := strconv.FormatFloat(cfg.FrameRate, 'f', -1, 64)
-	// Preserve aspect ratio: scale to fit inside a square target, then pad to square
-	target := cfg.FrameSize[0]
+	outputPattern := filepath.Join(videoDir, "frame_%05d.jpg")
+
+	switch cfg.SamplingMode {
+	case "", "fps":
+		return extractFramesFixedFPS(inputPath, videoDir, outputPattern, cfg)
+	case "scene", "hybrid":
+		return extractFramesBySceneChange(inputPath, videoDir, outputPattern, cfg)
+	default:
+		return fmt.Errorf("unknown sampling mode %q", cfg.SamplingMode)
+	}
+}
+
+func scaleAndPadFilters(target int) (scaleStr, padStr string) {
 	if target <= 0 {
 		target = 384
 	}
-	scaleStr := fmt.Sprintf("%d:%d", target, target)
-	padStr := fmt.Sprintf("%d:%d:(%d-iw)/2:(%d-ih)/2", target, target, target, target)
+	scaleStr = fmt.Sprintf("%d:%d", target, target)
+	padStr = fmt.Sprintf("%d:%d:(%d-iw)/2:(%d-ih)/2", target, target, target, target)
+	return scaleStr, padStr
+}
 
-	outputPattern := filepath.Join(videoDir, "frame_%05d.jpg")
+// extractFramesFixedFPS is the original, pre-scene-detection sampling path:
+// one frame every 1/cfg.FrameRate seconds. The frame_index.json sidecar is
+// derived directly from the frame rate rather than parsed from ffmpeg output.
+func extractFramesFixedFPS(inputPath, videoDir, outputPattern string, cfg Config) error {
+	fpsStr := strconv.FormatFloat(cfg.FrameRate, 'f', -1, 64)
+	scaleStr, padStr := scaleAndPadFilters(cfg.FrameSize[0])
 
-	return ffmpeg.
+	if err := ffmpeg.
 		Input(inputPath).
-		// Sample at cfg.FrameRate fps
 		Filter("fps", ffmpeg.Args{fpsStr}).
-		// Resize preserving aspect, then pad to square
 		Filter("scale", ffmpeg.Args{scaleStr}, ffmpeg.KwArgs{"force_original_aspect_ratio": "decrease"}).
 		Filter("pad", ffmpeg.Args{padStr}, ffmpeg.KwArgs{"color": "black"}).
-		// Write each frame as an image
 		Output(outputPattern, ffmpeg.KwArgs{"qscale:v": 1}).
 		OverWriteOutput().
-		Run()
+		Run(); err != nil {
+		return err
+	}
+
+	frameRate := cfg.FrameRate
+	if frameRate <= 0 {
+		frameRate = 1.0
+	}
+	frames, err := listJPEGFrames(videoDir)
+	if err != nil {
+		return fmt.Errorf("list extracted frames: %w", err)
+	}
+	entries := make([]FrameIndexEntry, 0, len(frames))
+	for i, name := range frames {
+		entries = append(entries, FrameIndexEntry{
+			Index:       i + 1,
+			TimestampMs: int64(float64(i) / frameRate * 1000),
+			Path:        name,
+		})
+	}
+	return writeFrameIndex(videoDir, entries)
+}
+
+// extractFramesBySceneChange drives ffmpeg's select filter directly rather
+// than sampling at a fixed rate, so a frame is only written at a shot
+// boundary. In hybrid mode the select expression also fires whenever
+// MaxIntervalSeconds has elapsed since the previous kept frame (using
+// select's own prev_selected_t bookkeeping), and suppresses any hit that
+// would land closer than MinIntervalSeconds to it; the first frame is always
+// kept, since prev_selected_t is undefined until then. The true timestamp of
+// each emitted frame is recovered by parsing pts_time out of the showinfo
+// filter's stderr log, since select output isn't evenly spaced and can't be
+// predicted from the frame's position alone.
+func extractFramesBySceneChange(inputPath, videoDir, outputPattern string, cfg Config) error {
+	threshold := cfg.SceneThreshold
+	if threshold <= 0 {
+		threshold = defaultSceneThreshold
+	}
+	minInterval := cfg.MinIntervalSeconds
+	if minInterval <= 0 {
+		minInterval = defaultMinIntervalSeconds
+	}
+	maxInterval := cfg.MaxIntervalSeconds
+	if maxInterval <= 0 {
+		maxInterval = defaultMaxIntervalSeconds
+	}
+
+	selectExpr := fmt.Sprintf("gt(scene,%s)", formatFloat(threshold))
+	if cfg.SamplingMode == "hybrid" {
+		selectExpr = fmt.Sprintf("isnan(prev_selected_t)+(%s+gte(t-prev_selected_t,%s))*gte(t-prev_selected_t,%s)",
+			selectExpr, formatFloat(maxInterval), formatFloat(minInterval))
+	}
+
+	scaleStr, padStr := scaleAndPadFilters(cfg.FrameSize[0])
+
+	stream := ffmpeg.
+		Input(inputPath).
+		Filter("select", ffmpeg.Args{selectExpr}).
+		Filter("showinfo", nil).
+		Filter("scale", ffmpeg.Args{scaleStr}, ffmpeg.KwArgs{"force_original_aspect_ratio": "decrease"}).
+		Filter("pad", ffmpeg.Args{padStr}, ffmpeg.KwArgs{"color": "black"}).
+		Output(outputPattern, ffmpeg.KwArgs{"qscale:v": 1, "vsync": "vfr"}).
+		OverWriteOutput()
+
+	var stderr bytes.Buffer
+	cmd := stream.Compile()
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("run ffmpeg: %w", err)
+	}
+
+	timestamps := parseShowinfoTimestamps(stderr.String())
+
+	frames, err := listJPEGFrames(videoDir)
+	if err != nil {
+		return fmt.Errorf("list extracted frames: %w", err)
+	}
+	entries := make([]FrameIndexEntry, 0, len(frames))
+	for i, name := range frames {
+		var ms int64
+		if i < len(timestamps) {
+			ms = int64(timestamps[i] * 1000)
+		}
+		entries = append(entries, FrameIndexEntry{
+			Index:       i + 1,
+			TimestampMs: ms,
+			Path:        name,
+		})
+	}
+	return writeFrameIndex(videoDir, entries)
+}
+
+// parseShowinfoTimestamps extracts, in order, every pts_time value logged by
+// the showinfo filter on ffmpeg's stderr.
+func parseShowinfoTimestamps(stderr string) []float64 {
+	matches := showinfoPTSTime.FindAllStringSubmatch(stderr, -1)
+	timestamps := make([]float64, 0, len(matches))
+	for _, m := range matches {
+		if t, err := strconv.ParseFloat(m[1], 64); err == nil {
+			timestamps = append(timestamps, t)
+		}
+	}
+	return timestamps
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func listJPEGFrames(videoDir string) ([]string, error) {
+	entries, err := os.ReadDir(videoDir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), "frame_") || !strings.HasSuffix(e.Name(), ".jpg") {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+	return names, nil
+}
+
+func writeFrameIndex(videoDir string, entries []FrameIndexEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode frame index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(videoDir, "frame_index.json"), data, 0o644); err != nil {
+		return fmt.Errorf("write frame index: %w", err)
+	}
+	return nil
+}
+
+// defaultAudioSegmentSeconds is used when ExtractAudioForVideo is called
+// with a non-positive segmentSeconds.
+const defaultAudioSegmentSeconds = 30
+
+// ExtractAudioForVideo writes a raw PCM stream (pcm_s16le, 48 kHz mono) plus
+// segmented WAV chunks of segmentSeconds each into
+// <framesRoot>/<name>/audio/, using a second ffmpeg pipeline invocation
+// alongside the frame extraction in ExtractFramesForVideo.
+func ExtractAudioForVideo(inputPath, framesRoot string, segmentSeconds int) error {
+	base := filepath.Base(inputPath)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+
+	audioDir := filepath.Join(framesRoot, name, "audio")
+	if err := os.MkdirAll(audioDir, 0o755); err != nil {
+		return fmt.Errorf("create audio dir: %w", err)
+	}
+
+	if segmentSeconds <= 0 {
+		segmentSeconds = defaultAudioSegmentSeconds
+	}
+
+	pcmPath := filepath.Join(audioDir, "audio.pcm")
+	if err := ffmpeg.
+		Input(inputPath).
+		Output(pcmPath, ffmpeg.KwArgs{
+			"f":      "s16le",
+			"acodec": "pcm_s16le",
+			"ar":     48000,
+			"ac":     1,
+		}).
+		OverWriteOutput().
+		Run(); err != nil {
+		return fmt.Errorf("extract pcm audio: %w", err)
+	}
+
+	segmentPattern := filepath.Join(audioDir, "segment_%05d.wav")
+	if err := ffmpeg.
+		Input(inputPath).
+		Output(segmentPattern, ffmpeg.KwArgs{
+			"f":            "segment",
+			"segment_time": segmentSeconds,
+			"acodec":       "pcm_s16le",
+			"ar":           48000,
+			"ac":           1,
+		}).
+		OverWriteOutput().
+		Run(); err != nil {
+		return fmt.Errorf("segment audio: %w", err)
+	}
+
+	return nil
 }
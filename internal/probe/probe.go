@@ -0,0 +1,97 @@
+// Package probe shells out to ffprobe to read a video's duration and stream
+// metadata before it is scheduled for frame extraction.
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Metadata holds the subset of ffprobe's output the daemon cares about.
+type Metadata struct {
+	DurationSeconds int
+	FrameRate       float64
+	Width           int
+	Height          int
+	Codec           string
+	Container       string
+}
+
+type ffprobeFormat struct {
+	Duration   string `json:"duration"`
+	FormatName string `json:"format_name"`
+}
+
+type ffprobeStream struct {
+	CodecType  string `json:"codec_type"`
+	CodecName  string `json:"codec_name"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	RFrameRate string `json:"r_frame_rate"`
+}
+
+type ffprobeOutput struct {
+	Format  ffprobeFormat   `json:"format"`
+	Streams []ffprobeStream `json:"streams"`
+}
+
+// Probe runs ffprobe against path and returns duration, native fps,
+// dimensions, codec, and container metadata for its first video stream.
+func Probe(ctx context.Context, path string) (Metadata, error) {
+	cmd := exec.CommandContext(ctx, "ffprobe",
+		"-v", "error",
+		"-show_streams",
+		"-show_format",
+		"-of", "json",
+		path,
+	)
+	out, err := cmd.Output()
+	if err != nil {
+		return Metadata{}, fmt.Errorf("run ffprobe: %w", err)
+	}
+
+	var parsed ffprobeOutput
+	if err := json.Unmarshal(out, &parsed); err != nil {
+		return Metadata{}, fmt.Errorf("decode ffprobe output: %w", err)
+	}
+
+	meta := Metadata{Container: parsed.Format.FormatName}
+	if seconds, err := strconv.ParseFloat(parsed.Format.Duration, 64); err == nil {
+		meta.DurationSeconds = int(seconds + 0.5)
+	}
+
+	for _, stream := range parsed.Streams {
+		if stream.CodecType != "video" {
+			continue
+		}
+		meta.Width = stream.Width
+		meta.Height = stream.Height
+		meta.Codec = stream.CodecName
+		meta.FrameRate = parseFrameRate(stream.RFrameRate)
+		break
+	}
+
+	return meta, nil
+}
+
+// parseFrameRate converts ffprobe's "num/den" rational frame rate into a
+// float, e.g. "30000/1001" -> 29.97.
+func parseFrameRate(raw string) float64 {
+	parts := strings.SplitN(raw, "/", 2)
+	if len(parts) != 2 {
+		return 0
+	}
+	num, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0
+	}
+	den, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil || den == 0 {
+		return 0
+	}
+	return num / den
+}
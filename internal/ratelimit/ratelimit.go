@@ -0,0 +1,117 @@
+// Package ratelimit provides a context-aware token-bucket limiter for
+// capping outbound requests-per-second to third-party services (vectordb,
+// cloud storage) without deploying a sidecar proxy.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Limiter is a token bucket: ratePerSec tokens are added per second up to
+// burst capacity, and Wait blocks the caller until a token is available or
+// ctx is done. A nil *Limiter, or one constructed with a non-positive rate,
+// never throttles.
+type Limiter struct {
+	ratePerSec float64
+	burst      int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+
+	waitCount    int64
+	droppedCount int64
+}
+
+// NewLimiter returns a Limiter allowing ratePerSec tokens per second, up to
+// burst tokens at once. ratePerSec <= 0 disables throttling entirely; Wait
+// then always returns immediately.
+func NewLimiter(ratePerSec float64, burst int) *Limiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &Limiter{
+		ratePerSec: ratePerSec,
+		burst:      burst,
+		tokens:     float64(burst),
+		last:       time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done, whichever comes
+// first, so callers cancelled mid-wait (e.g. via handleCancel) return
+// promptly instead of waiting out the full backoff.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil || l.ratePerSec <= 0 {
+		return nil
+	}
+	for {
+		wait, acquired := l.tryAcquire()
+		if acquired {
+			return nil
+		}
+		atomic.AddInt64(&l.waitCount, 1)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			atomic.AddInt64(&l.droppedCount, 1)
+			return ctx.Err()
+		}
+	}
+}
+
+// tryAcquire refills the bucket for elapsed time and, if a token is
+// available, takes it. Otherwise it reports how long until one will be.
+func (l *Limiter) tryAcquire() (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.ratePerSec
+	if l.tokens > float64(l.burst) {
+		l.tokens = float64(l.burst)
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0, true
+	}
+	missing := 1 - l.tokens
+	wait := time.Duration(missing / l.ratePerSec * float64(time.Second))
+	return wait + time.Millisecond, false
+}
+
+// Stats is a point-in-time snapshot of a Limiter's bucket state and
+// cumulative counters, returned by GET /ratelimit.
+type Stats struct {
+	RatePerSec   float64 `json:"rate_per_sec"`
+	Burst        int     `json:"burst"`
+	Tokens       float64 `json:"tokens"`
+	WaitCount    int64   `json:"wait_count"`
+	DroppedCount int64   `json:"dropped_count"`
+}
+
+// Stats returns the limiter's current token count and cumulative
+// wait/dropped-request counters. A nil Limiter reports an all-zero, unlimited
+// snapshot.
+func (l *Limiter) Stats() Stats {
+	if l == nil {
+		return Stats{}
+	}
+	l.mu.Lock()
+	tokens := l.tokens
+	l.mu.Unlock()
+	return Stats{
+		RatePerSec:   l.ratePerSec,
+		Burst:        l.burst,
+		Tokens:       tokens,
+		WaitCount:    atomic.LoadInt64(&l.waitCount),
+		DroppedCount: atomic.LoadInt64(&l.droppedCount),
+	}
+}
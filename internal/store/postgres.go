@@ -0,0 +1,270 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// schema creates the tables backing PostgresStore if they do not already exist.
+const schema = `
+CREATE TABLE IF NOT EXISTS videos (
+	id                    TEXT PRIMARY KEY,
+	path                  TEXT UNIQUE NOT NULL,
+	duration_seconds      INTEGER NOT NULL DEFAULT 0,
+	native_frame_rate     DOUBLE PRECISION NOT NULL DEFAULT 0,
+	width                 INTEGER NOT NULL DEFAULT 0,
+	height                INTEGER NOT NULL DEFAULT 0,
+	codec                 TEXT NOT NULL DEFAULT '',
+	container             TEXT NOT NULL DEFAULT '',
+	source_type           TEXT NOT NULL DEFAULT '',
+	source_url            TEXT NOT NULL DEFAULT '',
+	channel_id            TEXT NOT NULL DEFAULT '',
+	index_status          TEXT NOT NULL DEFAULT 'pending',
+	frames_extracted      INTEGER NOT NULL DEFAULT 0,
+	frames_uploaded       INTEGER NOT NULL DEFAULT 0,
+	total_frames_expected INTEGER NOT NULL DEFAULT 0,
+	last_indexed_at       TIMESTAMPTZ,
+	last_error            TEXT
+);
+
+CREATE TABLE IF NOT EXISTS jobs (
+	id         TEXT PRIMARY KEY,
+	video_id   TEXT NOT NULL REFERENCES videos(id),
+	type       TEXT NOT NULL,
+	status     TEXT NOT NULL,
+	progress   DOUBLE PRECISION NOT NULL DEFAULT 0,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS folders (
+	id           TEXT PRIMARY KEY,
+	path         TEXT UNIQUE NOT NULL,
+	recursive    BOOLEAN NOT NULL DEFAULT false,
+	status       TEXT NOT NULL DEFAULT 'scheduled',
+	watch        BOOLEAN NOT NULL DEFAULT false,
+	watch_status TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS sources (
+	id              TEXT PRIMARY KEY,
+	type            TEXT NOT NULL,
+	url             TEXT NOT NULL,
+	channel_id      TEXT NOT NULL DEFAULT '',
+	status          TEXT NOT NULL DEFAULT 'polling',
+	last_page_token TEXT NOT NULL DEFAULT '',
+	created_at      TIMESTAMPTZ NOT NULL
+);
+`
+
+// PostgresStore implements Store on top of a pgxpool connection pool.
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresStore connects to dsn and ensures the schema exists.
+func NewPostgresStore(ctx context.Context, dsn string) (*PostgresStore, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("connect postgres: %w", err)
+	}
+	if _, err := pool.Exec(ctx, schema); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("migrate schema: %w", err)
+	}
+	return &PostgresStore{pool: pool}, nil
+}
+
+func (p *PostgresStore) Close() {
+	p.pool.Close()
+}
+
+func (p *PostgresStore) UpsertVideo(ctx context.Context, v VideoRecord) error {
+	_, err := p.pool.Exec(ctx, `
+		INSERT INTO videos (id, path, duration_seconds, native_frame_rate, width, height, codec, container, source_type, source_url, channel_id, index_status, frames_extracted, frames_uploaded, total_frames_expected, last_indexed_at, last_error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		ON CONFLICT (id) DO UPDATE SET
+			duration_seconds = EXCLUDED.duration_seconds,
+			native_frame_rate = EXCLUDED.native_frame_rate,
+			width = EXCLUDED.width,
+			height = EXCLUDED.height,
+			codec = EXCLUDED.codec,
+			container = EXCLUDED.container,
+			source_type = EXCLUDED.source_type,
+			source_url = EXCLUDED.source_url,
+			channel_id = EXCLUDED.channel_id,
+			index_status = EXCLUDED.index_status,
+			frames_extracted = EXCLUDED.frames_extracted,
+			frames_uploaded = EXCLUDED.frames_uploaded,
+			total_frames_expected = EXCLUDED.total_frames_expected,
+			last_indexed_at = EXCLUDED.last_indexed_at,
+			last_error = EXCLUDED.last_error
+	`, v.ID, v.Path, v.DurationSeconds, v.NativeFrameRate, v.Width, v.Height, v.Codec, v.Container, v.SourceType, v.SourceURL, v.ChannelID, v.IndexStatus, v.FramesExtracted, v.FramesUploaded, v.TotalFramesExpected, v.LastIndexedAt, v.LastError)
+	if err != nil {
+		return fmt.Errorf("upsert video: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresStore) GetVideoByPath(ctx context.Context, path string) (VideoRecord, bool, error) {
+	row := p.pool.QueryRow(ctx, `
+		SELECT id, path, duration_seconds, native_frame_rate, width, height, codec, container, source_type, source_url, channel_id, index_status, frames_extracted, frames_uploaded, total_frames_expected, last_indexed_at, last_error
+		FROM videos WHERE path = $1
+	`, path)
+	var v VideoRecord
+	if err := row.Scan(&v.ID, &v.Path, &v.DurationSeconds, &v.NativeFrameRate, &v.Width, &v.Height, &v.Codec, &v.Container, &v.SourceType, &v.SourceURL, &v.ChannelID, &v.IndexStatus, &v.FramesExtracted, &v.FramesUploaded, &v.TotalFramesExpected, &v.LastIndexedAt, &v.LastError); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return VideoRecord{}, false, nil
+		}
+		return VideoRecord{}, false, fmt.Errorf("get video by path: %w", err)
+	}
+	return v, true, nil
+}
+
+func (p *PostgresStore) ListVideos(ctx context.Context) ([]VideoRecord, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT id, path, duration_seconds, native_frame_rate, width, height, codec, container, source_type, source_url, channel_id, index_status, frames_extracted, frames_uploaded, total_frames_expected, last_indexed_at, last_error
+		FROM videos
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list videos: %w", err)
+	}
+	defer rows.Close()
+
+	var videos []VideoRecord
+	for rows.Next() {
+		var v VideoRecord
+		if err := rows.Scan(&v.ID, &v.Path, &v.DurationSeconds, &v.NativeFrameRate, &v.Width, &v.Height, &v.Codec, &v.Container, &v.SourceType, &v.SourceURL, &v.ChannelID, &v.IndexStatus, &v.FramesExtracted, &v.FramesUploaded, &v.TotalFramesExpected, &v.LastIndexedAt, &v.LastError); err != nil {
+			return nil, fmt.Errorf("scan video: %w", err)
+		}
+		videos = append(videos, v)
+	}
+	return videos, rows.Err()
+}
+
+func (p *PostgresStore) CreateJob(ctx context.Context, j JobRecord) error {
+	_, err := p.pool.Exec(ctx, `
+		INSERT INTO jobs (id, video_id, type, status, progress, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, j.ID, j.VideoID, j.Type, j.Status, j.Progress, j.CreatedAt, j.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("create job: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresStore) UpdateJobProgress(ctx context.Context, j JobRecord) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE jobs SET status = $2, progress = $3, updated_at = $4 WHERE id = $1
+	`, j.ID, j.Status, j.Progress, j.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("update job progress: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresStore) ListPendingJobs(ctx context.Context) ([]JobRecord, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT id, video_id, type, status, progress, created_at, updated_at
+		FROM jobs WHERE status IN ('queued', 'running')
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list pending jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []JobRecord
+	for rows.Next() {
+		var j JobRecord
+		if err := rows.Scan(&j.ID, &j.VideoID, &j.Type, &j.Status, &j.Progress, &j.CreatedAt, &j.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan job: %w", err)
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+func (p *PostgresStore) MarkFrameUploaded(ctx context.Context, videoID string, uploaded, total int) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE videos SET frames_uploaded = $2, total_frames_expected = $3 WHERE id = $1
+	`, videoID, uploaded, total)
+	if err != nil {
+		return fmt.Errorf("mark frame uploaded: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresStore) UpsertFolder(ctx context.Context, f FolderRecord) error {
+	_, err := p.pool.Exec(ctx, `
+		INSERT INTO folders (id, path, recursive, status, watch, watch_status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			recursive = EXCLUDED.recursive,
+			status = EXCLUDED.status,
+			watch = EXCLUDED.watch,
+			watch_status = EXCLUDED.watch_status
+	`, f.ID, f.Path, f.Recursive, f.Status, f.Watch, f.WatchStatus)
+	if err != nil {
+		return fmt.Errorf("upsert folder: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresStore) ListFolders(ctx context.Context) ([]FolderRecord, error) {
+	rows, err := p.pool.Query(ctx, `SELECT id, path, recursive, status, watch, watch_status FROM folders`)
+	if err != nil {
+		return nil, fmt.Errorf("list folders: %w", err)
+	}
+	defer rows.Close()
+
+	var folders []FolderRecord
+	for rows.Next() {
+		var f FolderRecord
+		if err := rows.Scan(&f.ID, &f.Path, &f.Recursive, &f.Status, &f.Watch, &f.WatchStatus); err != nil {
+			return nil, fmt.Errorf("scan folder: %w", err)
+		}
+		folders = append(folders, f)
+	}
+	return folders, rows.Err()
+}
+
+func (p *PostgresStore) UpsertSource(ctx context.Context, src SourceRecord) error {
+	_, err := p.pool.Exec(ctx, `
+		INSERT INTO sources (id, type, url, channel_id, status, last_page_token, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			last_page_token = EXCLUDED.last_page_token
+	`, src.ID, src.Type, src.URL, src.ChannelID, src.Status, src.LastPageToken, src.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert source: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresStore) ListSources(ctx context.Context) ([]SourceRecord, error) {
+	rows, err := p.pool.Query(ctx, `
+		SELECT id, type, url, channel_id, status, last_page_token, created_at
+		FROM sources
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list sources: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []SourceRecord
+	for rows.Next() {
+		var src SourceRecord
+		if err := rows.Scan(&src.ID, &src.Type, &src.URL, &src.ChannelID, &src.Status, &src.LastPageToken, &src.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan source: %w", err)
+		}
+		sources = append(sources, src)
+	}
+	return sources, rows.Err()
+}
+
+var _ Store = (*PostgresStore)(nil)
@@ -0,0 +1,85 @@
+// Package store persists daemon state (videos, jobs, folders) so that an
+// in-process restart does not lose indexing progress.
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// VideoRecord is the persisted form of daemon.Video.
+type VideoRecord struct {
+	ID                  string
+	Path                string
+	DurationSeconds     int
+	NativeFrameRate     float64
+	Width               int
+	Height              int
+	Codec               string
+	Container           string
+	SourceType          string
+	SourceURL           string
+	ChannelID           string
+	IndexStatus         string
+	FramesExtracted     int
+	FramesUploaded      int
+	TotalFramesExpected int
+	LastIndexedAt       *time.Time
+	LastError           *string
+}
+
+// JobRecord is the persisted form of daemon.Job.
+type JobRecord struct {
+	ID        string
+	VideoID   string
+	Type      string
+	Status    string
+	Progress  float64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// FolderRecord is the persisted form of daemon.Folder.
+type FolderRecord struct {
+	ID          string
+	Path        string
+	Recursive   bool
+	Status      string
+	Watch       bool
+	WatchStatus string
+}
+
+// SourceRecord is the persisted form of daemon.URLSource. LastPageToken lets
+// channel polling resume where the previous poll left off after a restart.
+type SourceRecord struct {
+	ID            string
+	Type          string
+	URL           string
+	ChannelID     string
+	Status        string
+	LastPageToken string
+	CreatedAt     time.Time
+}
+
+// Store persists videos, jobs, folders, and remote sources so daemon state
+// survives restarts. Implementations must be safe for concurrent use.
+type Store interface {
+	UpsertVideo(ctx context.Context, video VideoRecord) error
+	GetVideoByPath(ctx context.Context, path string) (VideoRecord, bool, error)
+	ListVideos(ctx context.Context) ([]VideoRecord, error)
+
+	CreateJob(ctx context.Context, job JobRecord) error
+	UpdateJobProgress(ctx context.Context, job JobRecord) error
+	ListPendingJobs(ctx context.Context) ([]JobRecord, error)
+
+	MarkFrameUploaded(ctx context.Context, videoID string, uploaded, total int) error
+
+	UpsertFolder(ctx context.Context, folder FolderRecord) error
+	ListFolders(ctx context.Context) ([]FolderRecord, error)
+
+	UpsertSource(ctx context.Context, source SourceRecord) error
+	ListSources(ctx context.Context) ([]SourceRecord, error)
+
+	// Close releases the underlying connection pool.
+	Close()
+}
@@ -0,0 +1,18 @@
+// Package transcribe turns extracted audio segments into timestamped
+// transcript spans for indexing alongside visual frames.
+package transcribe
+
+import "context"
+
+// Segment is one transcribed span of speech, with times relative to the
+// start of the audio file passed to Transcribe.
+type Segment struct {
+	Start float64
+	End   float64
+	Text  string
+}
+
+// Transcriber turns an audio file into timestamped transcript segments.
+type Transcriber interface {
+	Transcribe(ctx context.Context, audioPath string) ([]Segment, error)
+}
@@ -0,0 +1,101 @@
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// WhisperServerTranscriber transcribes audio files through a whisper.cpp
+// server-style HTTP endpoint (POST /inference, multipart "file" field).
+type WhisperServerTranscriber struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewWhisperServerTranscriber builds a WhisperServerTranscriber for the
+// whisper-server instance at baseURL.
+func NewWhisperServerTranscriber(baseURL string) *WhisperServerTranscriber {
+	return &WhisperServerTranscriber{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+// Transcribe uploads audioPath to the whisper server and returns its
+// segments. Empty baseURL is treated as a configuration error rather than a
+// silent no-op, so callers notice a misconfigured WhisperURL immediately.
+func (t *WhisperServerTranscriber) Transcribe(ctx context.Context, audioPath string) ([]Segment, error) {
+	if t.baseURL == "" {
+		return nil, fmt.Errorf("whisper server url is empty")
+	}
+
+	file, err := os.Open(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("open audio segment: %w", err)
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filepath.Base(audioPath))
+	if err != nil {
+		return nil, fmt.Errorf("create form file: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, fmt.Errorf("copy audio segment: %w", err)
+	}
+	_ = writer.WriteField("response_format", "json")
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("finalize form: %w", err)
+	}
+
+	endpoint := t.baseURL + "/inference"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := t.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("whisper server request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		errBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("whisper server failed (%d): %s", resp.StatusCode, strings.TrimSpace(string(errBody)))
+	}
+
+	var payload struct {
+		Segments []struct {
+			Start float64 `json:"start"`
+			End   float64 `json:"end"`
+			Text  string  `json:"text"`
+		} `json:"segments"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decode whisper server response: %w", err)
+	}
+
+	segments := make([]Segment, 0, len(payload.Segments))
+	for _, seg := range payload.Segments {
+		text := strings.TrimSpace(seg.Text)
+		if text == "" {
+			continue
+		}
+		segments = append(segments, Segment{Start: seg.Start, End: seg.End, Text: text})
+	}
+	return segments, nil
+}
+
+var _ Transcriber = (*WhisperServerTranscriber)(nil)